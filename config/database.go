@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"lux-hotel/entity"
+	"lux-hotel/utils"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -33,10 +34,118 @@ func InitDB() {
 		panic("failed to connect database")
 	}
 
-	err = DB.AutoMigrate(&entity.User{}, &entity.TopUpTransaction{}, &entity.Hotel{}, &entity.Room{}, &entity.Payment{}, &entity.Booking{})
+	// The unique index on booking codes needs the old repeating codes replaced
+	// first. Runs once, the legacy column marks it as done.
+	if DB.Migrator().HasTable(&entity.Booking{}) && !DB.Migrator().HasColumn(&entity.Booking{}, "LegacyBookingCode") {
+		if err := DB.Transaction(reissueBookingCodes); err != nil {
+			panic("failed to reissue booking codes")
+		}
+	}
+
+	err = DB.AutoMigrate(&entity.User{}, &entity.TopUpTransaction{}, &entity.Hotel{}, &entity.HotelPhoto{}, &entity.RoomType{}, &entity.Room{}, &entity.Payment{}, &entity.Booking{}, &entity.HotelCharge{}, &entity.BookingCharge{}, &entity.PromoCode{}, &entity.PromoRedemption{}, &entity.BookingRoom{}, &entity.Review{}, &entity.Favorite{}, &entity.Invoice{}, &entity.Notification{}, &entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.CalendarFeed{}, &entity.AvailabilityBlock{}, &entity.PaymentDiscrepancy{}, &entity.WalletTransfer{}, &entity.WalletLedgerEntry{}, &entity.GiftCode{})
 	if err != nil {
 		panic("failed to migrate database")
 	}
 
+	backfillAmountPaid()
+	initSearchIndexes()
+
 	log.Println("Database connected")
 }
+
+// reissueBookingCodes gives every booking whose code is not a valid generated
+// code, such as the date based codes used before, a new one. The old code is
+// kept as the legacy code, guests have it in their confirmation emails.
+func reissueBookingCodes(tx *gorm.DB) error {
+	var bookings []struct {
+		ID          uint
+		BookingCode string
+	}
+
+	if err := tx.Migrator().AddColumn(&entity.Booking{}, "LegacyBookingCode"); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.Booking{}).Select("id, booking_code").Find(&bookings).Error; err != nil {
+		return err
+	}
+
+	taken := map[string]bool{}
+	for _, booking := range bookings {
+		taken[booking.BookingCode] = true
+	}
+
+	reissued := 0
+
+	for _, booking := range bookings {
+		if utils.ValidBookingCode(booking.BookingCode) {
+			continue
+		}
+
+		code, err := utils.GenerateBookingCode()
+		for err == nil && taken[code] {
+			code, err = utils.GenerateBookingCode()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.Booking{}).Where("id = ?", booking.ID).UpdateColumns(map[string]interface{}{
+			"booking_code":        code,
+			"legacy_booking_code": booking.BookingCode,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		taken[code] = true
+		reissued++
+	}
+
+	if reissued > 0 {
+		log.Printf("Reissued %d booking codes", reissued)
+	}
+
+	return nil
+}
+
+// backfillAmountPaid records bookings paid before deposits existed, which were
+// always paid in full, as fully paid
+func backfillAmountPaid() {
+	result := DB.Model(&entity.Booking{}).
+		Where("booking_status IN ? AND amount_paid = 0", []string{"settlement", "checked_in", "checked_out", "no_show"}).
+		UpdateColumn("amount_paid", gorm.Expr("total_price"))
+
+	if result.Error != nil {
+		panic("failed to backfill paid amounts")
+	}
+
+	if result.RowsAffected > 0 {
+		log.Printf("Recorded %d paid bookings as paid in full", result.RowsAffected)
+	}
+}
+
+// initSearchIndexes sets up the full-text and trigram indexes used by hotel search.
+// Failures are logged only, so the API still starts on databases without pg_trgm.
+func initSearchIndexes() {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`ALTER TABLE hotels ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(location, '')), 'B') ||
+			setweight(to_tsvector('simple', coalesce(amenities, '')), 'C') ||
+			setweight(to_tsvector('simple', coalesce(description, '')), 'D')
+		) STORED`,
+		"CREATE INDEX IF NOT EXISTS idx_hotels_search_vector ON hotels USING GIN (search_vector)",
+		"CREATE INDEX IF NOT EXISTS idx_hotels_name_trgm ON hotels USING GIN (name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_hotels_location_trgm ON hotels USING GIN (location gin_trgm_ops)",
+	}
+
+	for _, statement := range statements {
+		if err := DB.Exec(statement).Error; err != nil {
+			log.Printf("Failed to set up hotel search: %v", err)
+			return
+		}
+	}
+}
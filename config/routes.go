@@ -4,6 +4,7 @@ import (
 	customeMiddleware "lux-hotel/middleware"
 	"lux-hotel/repository"
 	"lux-hotel/service"
+	"lux-hotel/utils"
 	"net/http"
 
 	_ "lux-hotel/docs"
@@ -25,6 +26,44 @@ func Routes(DB *gorm.DB) {
 	midtransService := service.NewMidtransService(midtransRepository)
 	paymentRepository := repository.NewPaymentRepository(DB)
 	paymentService := service.NewPaymentService(paymentRepository)
+	chargeRepository := repository.NewChargeRepository(DB)
+	chargeService := service.NewChargeService(chargeRepository)
+	promoRepository := repository.NewPromoRepository(DB)
+	promoService := service.NewPromoService(promoRepository)
+	roomTypeRepository := repository.NewRoomTypeRepository(DB)
+	roomTypeService := service.NewRoomTypeService(roomTypeRepository)
+	hotelContentRepository := repository.NewHotelContentRepository(DB)
+	hotelContentService := service.NewHotelContentService(hotelContentRepository)
+	reviewRepository := repository.NewReviewRepository(DB)
+	reviewService := service.NewReviewService(reviewRepository)
+	invoiceRepository := repository.NewInvoiceRepository(DB)
+	invoiceService := service.NewInvoiceService(invoiceRepository)
+	notificationRepository := repository.NewNotificationRepository(DB)
+	notificationWorker := service.NewNotificationWorker(notificationRepository, utils.NewMailer())
+	webhookRepository := repository.NewWebhookRepository(DB)
+	webhookService := service.NewWebhookService(webhookRepository)
+	webhookWorker := service.NewWebhookWorker(webhookRepository)
+	calendarRepository := repository.NewCalendarRepository(DB)
+	calendarService := service.NewCalendarService(calendarRepository)
+	calendarWorker := service.NewCalendarWorker(calendarRepository)
+	balanceRepository := repository.NewBalanceRepository(DB)
+	balanceWorker := service.NewBalanceWorker(balanceRepository)
+	bookingHoldRepository := repository.NewBookingHoldRepository(DB)
+	bookingHoldWorker := service.NewBookingHoldWorker(bookingHoldRepository)
+	reconciliationRepository := repository.NewReconciliationRepository(DB)
+	reconciliationService := service.NewReconciliationService(reconciliationRepository)
+	reconciliationWorker := service.NewReconciliationWorker(reconciliationRepository)
+	housekeepingRepository := repository.NewHousekeepingRepository(DB)
+	housekeepingService := service.NewHousekeepingService(housekeepingRepository)
+	walletRepository := repository.NewWalletRepository(DB)
+	walletService := service.NewWalletService(walletRepository)
+
+	notificationWorker.Start()
+	webhookWorker.Start()
+	calendarWorker.Start()
+	reconciliationWorker.Start()
+	balanceWorker.Start()
+	bookingHoldWorker.Start()
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:5173"},
@@ -41,18 +80,92 @@ func Routes(DB *gorm.DB) {
 	api.GET("/users/balance", userService.GetBalance, customeMiddleware.ValidateJWTMiddleware)
 	api.POST("/users/balance/top-up", userService.TopUpBalance, customeMiddleware.ValidateJWTMiddleware)
 	api.GET("/users/book/history", userService.GetBookHistory, customeMiddleware.ValidateJWTMiddleware)
+	api.GET("/users/favorites", userService.GetFavorites, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/users/favorites/:hotel_id", userService.AddFavorite, customeMiddleware.ValidateJWTMiddleware)
+	api.DELETE("/users/favorites/:hotel_id", userService.RemoveFavorite, customeMiddleware.ValidateJWTMiddleware)
+
+	// Wallet
+	api.POST("/wallet/transfers", walletService.CreateTransfer, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/wallet/transfers/:transfer_id/confirm", walletService.ConfirmTransfer, customeMiddleware.ValidateJWTMiddleware)
+	api.GET("/wallet/ledger", walletService.GetLedger, customeMiddleware.ValidateJWTMiddleware)
+	api.GET("/wallet/gift-codes", walletService.GetMyGiftCodes, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/wallet/gift-codes", walletService.PurchaseGiftCode, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/wallet/gift-codes/redeem", walletService.RedeemGiftCode, customeMiddleware.ValidateJWTMiddleware)
 
 	// Hotel
-	api.GET("/hotel-list", hotelService.GetHotelList)
-	api.GET("/hotel/:id", hotelService.GetHotelDetail)
+	api.GET("/hotel-list", hotelService.GetHotelList, customeMiddleware.OptionalJWTMiddleware)
+	api.GET("/hotel/:id", hotelService.GetHotelDetail, customeMiddleware.OptionalJWTMiddleware)
+	api.GET("/hotel/:id/reviews", reviewService.GetHotelReviews)
+	api.GET("/hotels/search", hotelService.SearchHotels)
 	api.POST("/hotel/:id/booking", hotelService.Booking, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/hotel/:id/quote", hotelService.Quote)
+	api.GET("/rooms/:id/calendar.ics", calendarService.GetRoomCalendar)
+
+	// Booking
+	api.GET("/bookings/:order_id", hotelService.GetBookingDetail, customeMiddleware.ValidateJWTMiddleware)
+	api.GET("/bookings/:order_id/invoice", invoiceService.DownloadInvoice, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/bookings/:order_id/rooms/:line_id/cancel", hotelService.CancelBookingRoom, customeMiddleware.ValidateJWTMiddleware)
+	api.POST("/bookings/:order_id/review", reviewService.CreateReview, customeMiddleware.ValidateJWTMiddleware)
 
 	// Payment
 	api.POST("/order/payment", paymentService.Payment, customeMiddleware.ValidateJWTMiddleware)
+	api.GET("/payment-methods", paymentService.GetPaymentMethods)
 
 	// Midtrans Callback
 	api.POST("/midtrans/callback", midtransService.HandleMidtransCallback)
 
+	// Admin
+	admin := api.Group("/admin", customeMiddleware.ValidateJWTMiddleware, customeMiddleware.RequireRoleMiddleware("admin"))
+	admin.PUT("/hotels/:id", hotelContentService.UpdateHotelContent)
+	admin.PUT("/hotels/:id/deposit-policy", hotelContentService.UpdateDepositPolicy)
+	admin.POST("/hotels/:id/photos", hotelContentService.AddHotelPhoto)
+	admin.PUT("/hotels/:id/photos/order", hotelContentService.ReorderHotelPhotos)
+	admin.PUT("/photos/:id", hotelContentService.UpdateHotelPhoto)
+	admin.DELETE("/photos/:id", hotelContentService.DeleteHotelPhoto)
+	admin.GET("/hotels/:id/charges", chargeService.GetHotelCharges)
+	admin.POST("/hotels/:id/charges", chargeService.CreateHotelCharge)
+	admin.PUT("/charges/:id", chargeService.UpdateHotelCharge)
+	admin.DELETE("/charges/:id", chargeService.DeleteHotelCharge)
+	admin.GET("/promos", promoService.GetPromoCodes)
+	admin.POST("/promos", promoService.CreatePromoCode)
+	admin.PUT("/promos/:id", promoService.UpdatePromoCode)
+	admin.GET("/promos/:id/redemptions", promoService.GetPromoRedemptions)
+	admin.POST("/hotels/:id/room-types", roomTypeService.CreateRoomType)
+	admin.PUT("/room-types/:id", roomTypeService.UpdateRoomType)
+	admin.DELETE("/room-types/:id", roomTypeService.DeleteRoomType)
+	admin.PUT("/rooms/:id/room-type", roomTypeService.SetRoomType)
+	admin.GET("/rooms/:id/calendar", calendarService.GetCalendarExport)
+	admin.GET("/rooms/:id/calendar-feeds", calendarService.GetCalendarFeeds)
+	admin.POST("/rooms/:id/calendar-feeds", calendarService.CreateCalendarFeed)
+	admin.DELETE("/calendar-feeds/:id", calendarService.DeleteCalendarFeed)
+	admin.POST("/calendar-feeds/:id/sync", calendarService.SyncCalendarFeed)
+	admin.GET("/rooms/:id/availability-blocks", calendarService.GetAvailabilityBlocks)
+	admin.POST("/bookings/:order_id/assign-room", hotelService.AssignBookingRoom)
+	admin.GET("/reviews", reviewService.GetReviews)
+	admin.PUT("/reviews/:id/moderate", reviewService.ModerateReview)
+	admin.POST("/reviews/:id/reply", reviewService.ReplyReview)
+	admin.GET("/webhooks", webhookService.GetSubscriptions)
+	admin.POST("/webhooks", webhookService.CreateSubscription)
+	admin.PUT("/webhooks/:id", webhookService.UpdateSubscription)
+	admin.DELETE("/webhooks/:id", webhookService.DeleteSubscription)
+	admin.GET("/webhooks/:id/deliveries", webhookService.GetDeliveries)
+	admin.POST("/webhook-deliveries/:id/redeliver", webhookService.Redeliver)
+	admin.POST("/reconciliation/run", reconciliationService.RunReconciliation)
+	admin.GET("/payment-discrepancies", reconciliationService.GetDiscrepancies)
+	admin.PUT("/payment-discrepancies/:id/resolve", reconciliationService.ResolveDiscrepancy)
+	admin.GET("/gift-codes", walletService.GetGiftCodes)
+	admin.POST("/gift-codes", walletService.IssueGiftCode)
+
+	// Front desk
+	staff := api.Group("/staff", customeMiddleware.ValidateJWTMiddleware, customeMiddleware.RequireRoleMiddleware("staff", "admin"))
+	staff.POST("/bookings/:booking_code/check-in", hotelService.CheckIn)
+	staff.POST("/bookings/:booking_code/check-out", hotelService.CheckOut)
+	staff.POST("/bookings/:booking_code/no-show", hotelService.MarkNoShow)
+	staff.GET("/hotels/:id/housekeeping", housekeepingService.GetHousekeepingTasks)
+	staff.PUT("/rooms/:id/housekeeping", housekeepingService.UpdateRoomHousekeeping)
+
+	e.Static(utils.UploadURLPrefix, utils.UploadDir())
+
 	api.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	e.Logger.Fatal(e.Start(":8080"))
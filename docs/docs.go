@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/hotel-list": {
-            "get": {
-                "description": "Fetches all hotels available in the system and returns them.",
+        "/api/admin/bookings/{order_id}/assign-room": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Puts a specific room on a booking line, typically one booked by room type. The room must be of the booked type and free for the stay. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,16 +30,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "hotel"
+                    "admin"
+                ],
+                "summary": "Assign a room to a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking line and room",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AssignBookingRoomPayload"
+                        }
+                    }
                 ],
-                "summary": "Get a list of hotels",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved hotel list",
+                        "description": "Room assigned successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking or room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Room is already booked for these dates",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -44,9 +97,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/hotel/{id}": {
-            "get": {
-                "description": "Fetches the details of a hotel by its ID and returns the hotel information.",
+        "/api/admin/calendar-feeds/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes the calendar and the availability blocks it created. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -54,13 +112,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "hotel"
+                    "admin"
                 ],
-                "summary": "Get details of a specific hotel",
+                "summary": "Delete a room calendar import",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Hotel ID",
+                        "description": "Calendar feed ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -68,7 +126,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved hotel details",
+                        "description": "Calendar feed deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
@@ -79,8 +137,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "404": {
-                        "description": "Hotel not found",
+                        "description": "Calendar feed not found",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -94,14 +164,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/hotel/{id}/booking": {
+        "/api/admin/calendar-feeds/{id}/sync": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Allows a user to book a room in a specified hotel. Requires a valid JWT token for authentication and hotel ID in the URL.",
+                "description": "Imports the calendar immediately instead of waiting for the periodic sync. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -109,36 +179,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "hotel"
+                    "admin"
                 ],
-                "summary": "Book a room in a hotel",
+                "summary": "Sync a room calendar import",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Hotel ID",
+                        "description": "Calendar feed ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Booking details",
-                        "name": "booking_request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.BookingRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Room booked successfully",
+                        "description": "Calendar feed synced successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID or Invalid request",
+                        "description": "Invalid ID or calendar import failed",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -149,6 +210,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Calendar feed not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -158,14 +231,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/order/payment": {
-            "post": {
+        "/api/admin/charges/{id}": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Processes a payment order, requiring a valid JWT token for authentication. The request body should contain payment details.",
+                "description": "Replaces the configuration of an existing charge rule. Bookings already made keep the amounts they were quoted. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,23 +246,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
-                "summary": "Process a payment order",
+                "summary": "Update a hotel tax or fee",
                 "parameters": [
                     {
-                        "description": "Payment details",
-                        "name": "payment_request",
+                        "type": "integer",
+                        "description": "Charge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Charge rule",
+                        "name": "charge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.PaymentPayload"
+                            "$ref": "#/definitions/entity.HotelChargePayload"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment processed successfully",
+                        "description": "Charge updated successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
@@ -206,6 +286,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Charge not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -213,16 +305,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users/balance": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the current balance of the user from the database based on the user ID obtained from the JWT token.",
+                "description": "Removes a charge rule from its hotel. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,18 +320,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
+                ],
+                "summary": "Delete a hotel tax or fee",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Charge ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get the balance of the logged-in user",
                 "responses": {
                     "200": {
-                        "description": "User balance retrieved successfully",
+                        "description": "Charge deleted successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -252,6 +351,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Charge not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -261,50 +372,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/balance/top-up": {
-            "post": {
+        "/api/admin/gift-codes": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Allows the user to top up their balance by providing the amount and other relevant information. The request must include a valid JWT token for authentication.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns all gift codes, bought or issued, newest first. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Top up the balance of the logged-in user",
-                "parameters": [
-                    {
-                        "description": "User top-up balance data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.UserTopUpBalancePayload"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List gift codes",
                 "responses": {
                     "200": {
-                        "description": "User balance topped up successfully",
+                        "description": "Gift codes retrieved successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
-                    "400": {
-                        "description": "Invalid top-up data",
+                    "401": {
+                        "description": "Unauthorized access",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized access",
+                    "403": {
+                        "description": "Insufficient permission",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -316,16 +413,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/users/book/history": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches the booking history for the logged-in user based on the user ID extracted from the JWT token.",
+                "description": "Issues a gift balance code worth between 50.000 and 5.000.000 IDR that is not paid from any wallet, e.g. for corporate accounts funding employees. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -333,18 +428,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
+                ],
+                "summary": "Issue a gift code",
+                "parameters": [
+                    {
+                        "description": "Gift code",
+                        "name": "gift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GiftCodePayload"
+                        }
+                    }
                 ],
-                "summary": "Get user booking history",
                 "responses": {
-                    "200": {
-                        "description": "User booking history retrieved successfully",
+                    "201": {
+                        "description": "Gift code issued successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -355,6 +461,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -364,9 +476,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/login": {
-            "post": {
-                "description": "Logs the user in by validating their credentials and returning a JWT token for authentication.",
+        "/api/admin/hotels/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the hotel's name, contact details, description, star rating, check-in/check-out times, amenity tags and coordinates. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -374,29 +491,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Login a user and return a JWT token",
+                "summary": "Update hotel content",
                 "parameters": [
                     {
-                        "description": "User login data",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hotel content",
+                        "name": "content",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UserLoginPayload"
+                            "$ref": "#/definitions/entity.HotelContentPayload"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User logged in successfully",
+                        "description": "Hotel updated successfully",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
                     },
                     "400": {
-                        "description": "Invalid login credentials",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -407,6 +531,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ResponseError"
                         }
                     },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -416,9 +552,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/users/register": {
-            "post": {
-                "description": "Registers a new user in the system. It validates the input, checks for errors, and stores the user data in the database.",
+        "/api/admin/hotels/{id}/charges": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every tax and service charge rule configured for the hotel, active or not. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -426,23 +567,3606 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "List hotel taxes and fees",
                 "parameters": [
                     {
-                        "description": "User registration data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.UserRegisterPayload"
-                        }
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User successfully registered",
+                    "200": {
+                        "description": "Charges retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a percentage or fixed charge, per night or per stay, inclusive or exclusive of the room rate. Percentages are taken of the room subtotal net of every inclusive charge. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a hotel tax or fee",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Charge rule",
+                        "name": "charge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HotelChargePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Charge created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/hotels/{id}/deposit-policy": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lets stays of at least deposit_min_nights be booked with deposit_percent of the total. The balance is due balance_due_days before check-in, or at the front desk when balance_at_desk is set. Bookings not paid by the due date are cancelled and keep the deposit. A deposit_percent of 0 requires full payment at booking. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update hotel deposit policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Deposit policy",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DepositPolicyPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deposit policy updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/hotels/{id}/photos": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Uploads a JPEG or PNG image (max 5 MB) to the hotel gallery and generates a thumbnail. Admin only.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Upload a hotel photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position in the gallery",
+                        "name": "sort_order",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Photo uploaded successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or image",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/hotels/{id}/photos/order": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Orders the gallery by the given list of photo IDs. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reorder hotel photos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Photo IDs in display order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HotelPhotoOrderPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Photos reordered successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel or photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/hotels/{id}/room-types": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a room type with capacity, beds, size, amenities, photos and base rate. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room type",
+                        "name": "room_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RoomTypePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Room type created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/payment-discrepancies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists discrepancies between payments and Midtrans, newest first: missed settlements, amount mismatches, duplicate payments and payments Midtrans does not know. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get payment discrepancies",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only resolved or only open discrepancies",
+                        "name": "resolved",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Discrepancies retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/payment-discrepancies/{id}/resolve": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks a discrepancy as handled, e.g. after refunding a duplicate payment. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resolve a payment discrepancy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Discrepancy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Discrepancy resolved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Discrepancy not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/photos/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the caption and sort order of a gallery photo. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a hotel photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Photo details",
+                        "name": "photo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HotelPhotoPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Photo updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a photo from the gallery and deletes the stored image and thumbnail. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a hotel photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Photo deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/promos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns all promo codes with their hotel restrictions. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List promo codes",
+                "responses": {
+                    "200": {
+                        "description": "Promo codes retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a percentage or fixed discount code with optional validity window, usage limits, minimum stay and hotel restrictions. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a promo code",
+                "parameters": [
+                    {
+                        "description": "Promo code",
+                        "name": "promo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PromoCodePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Promo code created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Promo code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/promos/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the rules of a promo code. The code itself cannot be changed; set active to false to retire it. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a promo code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promo code",
+                        "name": "promo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PromoCodePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promo code updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Promo code not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/promos/{id}/redemptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every booking that used the promo code with its status: held while unpaid, redeemed once paid, released when the booking expired or was cancelled. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List promo code redemptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Promo code ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redemptions retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Promo code not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reconciliation/run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Looks up pending payments older than 15 minutes at Midtrans, applies the status a missed callback would have and reports discrepancies. The same job runs every 10 minutes. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Run payment reconciliation",
+                "responses": {
+                    "200": {
+                        "description": "Reconciliation completed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns reviews of every hotel, newest first, optionally filtered by moderation status. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved reviews",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reviews/{id}/moderate": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves or rejects a review. Only approved reviews are published and counted in the hotel's average rating. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Moderate a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Moderation decision",
+                        "name": "moderation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewModerationPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review moderated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reviews/{id}/reply": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets or replaces the hotel's public reply to a review. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reply to a review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reply",
+                        "name": "reply",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewReplyPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reply saved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/room-types/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the details of a room type. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room type",
+                        "name": "room_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RoomTypePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room type updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room type not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a room type that no room references any more and no upcoming booking holds. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a room type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room type deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or room type has upcoming bookings",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room type not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Room type still has rooms",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/rooms/{id}/availability-blocks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the current and upcoming blocks imported for the room. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List room availability blocks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability blocks retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/rooms/{id}/calendar": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the secret ICS feed URL of a room to register on other OTAs. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get room calendar feed URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Calendar feed URL retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/rooms/{id}/calendar-feeds": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the external ICS calendars that block the room, with their last sync result. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List room calendar imports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Calendar feeds retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers an ICS calendar by http(s) URL or local file path. Its events become availability blocks that bookings of the room cannot overlap. The calendar is imported right away and then re-read periodically. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Add a room calendar import",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Calendar source",
+                        "name": "feed",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CalendarFeedPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Calendar feed created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/rooms/{id}/room-type": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Links a room to a room type of the same hotel, or unlinks it when room_type_id is null. A room cannot leave a type whose remaining rooms would be fewer than its upcoming bookings on any night. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set the room type of a room",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Room type",
+                        "name": "room_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RoomTypeAssignPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or the type would be overbooked",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room or room type not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhook-deliveries/{id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends a delivered or failed event again with the same event ID and payload, starting a fresh round of retries. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Redeliver a webhook event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery queued",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or delivery already queued",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery or subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every partner webhook subscription. The signing secret is not included, it is only returned when the subscription is created. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "Subscriptions retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers a partner URL for booking.created, booking.settled, booking.cancelled, booking.expired and topup.settled events. Each delivery is signed with the returned secret, which is shown only this once: X-Webhook-Signature is \"sha256=\" followed by the hex HMAC-SHA256 of \"{X-Webhook-Timestamp}.{raw body}\". Failed deliveries are retried with exponential backoff. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookSubscriptionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhooks/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name, URL and events of a subscription and optionally pauses it. The secret stays the same. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.WebhookSubscriptionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a subscription. Its delivery log is kept and queued deliveries are marked failed. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the latest 100 deliveries of a subscription with attempts, response status and last error, newest first. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bookings/{order_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns one booking with its hotel, rooms, guest, charges, latest payment (method, VA number and status), all payments, the amount paid and any balance still due, and a timeline of what happened to it. Usable as a receipt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Get booking detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Booking retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bookings/{order_id}/invoice": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the invoice and booking confirmation of a paid booking as a PDF. Invoice numbers run sequentially per hotel and the room lines and totals are stored when the invoice is issued, so later changes to the booking do not alter them. Every settled payment is listed, with the amount paid and any balance still due. Available to the guest and to admins.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Download booking invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Booking is not paid",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bookings/{order_id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a review with an overall rating and optional category ratings (1-5) for a paid booking whose check-out date has passed. One review per booking; reviews are published after moderation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Review a stay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review submitted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or stay not completed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking has already been reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/bookings/{order_id}/rooms/{line_id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes one room from a multi-room booking before check-in and re-prices the rest. Paid bookings are refunded what was paid beyond the new total to the wallet balance. Cancelling the last room cancels the booking and refunds everything paid. Not available while a bank payment is pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Cancel a room from a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Booking room line ID, the id of an entry in the booking's rooms",
+                        "name": "line_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room cancelled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or booking cannot be changed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking or room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotel-list": {
+            "get": {
+                "description": "Fetches all hotels available in the system and returns them. Passing lat and lng adds the distance to each hotel and, with radius_km, keeps only hotels within that radius. With a token, is_favorite marks the user's favourite hotels.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Get a list of hotels",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude of the search point",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the search point",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in kilometres",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum room price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum room price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "distance, price_asc, price_desc or rating",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved hotel list",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotel/{id}": {
+            "get": {
+                "description": "Fetches the details of a hotel by its ID and returns the hotel information. With a token, is_favorite tells whether the user saved the hotel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Get details of a specific hotel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved hotel details",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotel/{id}/booking": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Allows a user to book a room in a specified hotel. Requires a valid JWT token for authentication and hotel ID in the URL. A booking expires and releases its rooms 30 minutes after it is made unless a payment has been started.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Book a room in a hotel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking details",
+                        "name": "booking_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Room booked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotel/{id}/quote": {
+            "post": {
+                "description": "Calculates the room subtotal, taxes and service charges for the requested stay without creating a booking. When the hotel's deposit policy covers the stay, the deposit and the date the balance is due are included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Get a price quote for a room",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking details",
+                        "name": "booking_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quote calculated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel or room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotel/{id}/reviews": {
+            "get": {
+                "description": "Returns approved reviews of a hotel, newest first, with overall and category rating averages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get hotel reviews",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved reviews",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/hotels/search": {
+            "get": {
+                "description": "Full-text search over hotel name, location, amenities and description with prefix matching and typo tolerance. Results are ranked and include a snippet with the matched words wrapped in \u003cmark\u003e tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Search hotels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully searched hotels",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/payment": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Processes a payment order, requiring a valid JWT token for authentication. The request body should contain payment details. payment_method is a code from /api/payment-methods, credit_card also needs a Midtrans card_token. The method's fee is added to the amount due and the response carries the VA, bill key, QR code, deeplink or 3DS redirect the method needs. Calling it again with the same bank returns the pending virtual account; another method cancels the pending charge and starts a new attempt while the booking stays held. Set deposit to pay only the deposit of a booking that offers one; paying a confirmed booking with an open balance pays the balance. Set use_wallet to take what the wallet balance covers and charge the rest with payment_method; the wallet part is held until that charge settles and returned if it expires, is cancelled or is refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Process a payment order",
+                "parameters": [
+                    {
+                        "description": "Payment details",
+                        "name": "payment_request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PaymentPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment processed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Pending payment could not be cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment-methods": {
+            "get": {
+                "description": "Lists the enabled payment methods with display name, logo, fee and the amounts they accept. The code goes into payment_method of /api/order/payment.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Get payment methods",
+                "responses": {
+                    "200": {
+                        "description": "Payment methods retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/rooms/{id}/calendar.ics": {
+            "get": {
+                "description": "Exports the room's upcoming bookings as an iCalendar feed for other OTAs. The token comes from the admin calendar export endpoint.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "hotel"
+                ],
+                "summary": "Room availability calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calendar token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ICS calendar",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/staff/bookings/{booking_code}/check-in": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks in a paid booking by its booking code on or after the check-in day. Records the guest's ID, assigns rooms to lines that have none yet and marks the rooms occupied. A deposit booking's outstanding balance must be collected at the desk and its method given as balance_payment_method. Staff and admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Check a guest in",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted",
+                        "name": "booking_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Guest ID and room assignments",
+                        "name": "check_in",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckInPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Guest checked in successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking or room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Room is occupied, not cleaned or out of order, or the balance is being paid online",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/staff/bookings/{booking_code}/check-out": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks out an in-house booking by its booking code, releases its rooms and marks them dirty for housekeeping. A late checkout fee is added to the booking total and its invoice as a charge line and recorded as paid at the desk with the given payment method. Staff and admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Check a guest out",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted",
+                        "name": "booking_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Late checkout fee and how it was collected",
+                        "name": "check_out",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckOutPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Guest checked out successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/staff/bookings/{booking_code}/no-show": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marks a paid booking as no-show by its booking code once its check-in day has passed. Staff and admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Mark a booking as no-show",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted",
+                        "name": "booking_code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Booking marked as no-show",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Booking cannot be marked as no-show",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Booking not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/staff/hotels/{id}/housekeeping": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the rooms of a hotel that need cleaning, stay-over service, inspection before an arrival or maintenance on a day. Rooms with arrivals come first. Staff and admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Get the daily housekeeping task list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day as YYYY-MM-DD, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Housekeeping tasks retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/staff/rooms/{id}/housekeeping": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a room between clean, dirty, inspected and out_of_order. Dirty rooms can only be cleaned or taken out of order, and only clean rooms can be inspected. Staff and admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "staff"
+                ],
+                "summary": "Update the housekeeping status of a room",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Housekeeping status",
+                        "name": "housekeeping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HousekeepingPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Housekeeping status updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Room not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/balance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the current balance of the user from the database based on the user ID obtained from the JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the balance of the logged-in user",
+                "responses": {
+                    "200": {
+                        "description": "User balance retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/balance/top-up": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Allows the user to top up their balance by providing the amount and other relevant information. The request must include a valid JWT token for authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Top up the balance of the logged-in user",
+                "parameters": [
+                    {
+                        "description": "User top-up balance data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserTopUpBalancePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User balance topped up successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid top-up data",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/book/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the booking history for the logged-in user based on the user ID extracted from the JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user booking history",
+                "responses": {
+                    "200": {
+                        "description": "User booking history retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the user's saved hotels, most recently saved first, in the same shape as the hotel list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get favourite hotels",
+                "responses": {
+                    "200": {
+                        "description": "User favorites retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/favorites/{hotel_id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saves a hotel to the user's wishlist. Adding a hotel that is already saved succeeds without changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Add a favourite hotel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hotel added to favorites",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a hotel from the user's wishlist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Remove a favourite hotel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hotel ID",
+                        "name": "hotel_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hotel removed from favorites",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Hotel is not in favorites",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/login": {
+            "post": {
+                "description": "Logs the user in by validating their credentials and returning a JWT token for authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Login a user and return a JWT token",
+                "parameters": [
+                    {
+                        "description": "User login data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserLoginPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User logged in successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid login credentials",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/register": {
+            "post": {
+                "description": "Registers a new user in the system. It validates the input, checks for errors, and stores the user data in the database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserRegisterPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User successfully registered",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseOK"
                         }
@@ -454,7 +4178,350 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Email already exists",
+                        "description": "Email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/gift-codes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the gift codes bought by the logged-in user, newest first, and whether they were redeemed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get purchased gift codes",
+                "responses": {
+                    "200": {
+                        "description": "Gift codes retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Buys a gift balance code worth between 50.000 and 5.000.000 IDR, paid from the wallet. Anyone holding the code can redeem it into their wallet within a year. Counts towards the daily limit of 10.000.000 IDR.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Buy a gift code",
+                "parameters": [
+                    {
+                        "description": "Gift code",
+                        "name": "gift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GiftCodePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Gift code purchased successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, insufficient balance or daily limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/gift-codes/redeem": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds the value of a gift code to the logged-in user's balance. Each code can be redeemed once; dashes and lower case are accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Redeem a gift code",
+                "parameters": [
+                    {
+                        "description": "Gift code",
+                        "name": "gift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RedeemGiftCodePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Gift code redeemed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or gift code expired",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Gift code not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Gift code has already been redeemed",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/ledger": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists every change of the wallet balance, newest first, with the balance after each entry: top-ups, booking payments and refunds, amounts held for split payments and their release, transfers and gift codes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the wallet ledger",
+                "responses": {
+                    "200": {
+                        "description": "Ledger retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/transfers": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Starts sending balance to the user with the given email or phone number. Returns the shortened recipient name to check; nothing is sent until the transfer is confirmed with the sender's password within 10 minutes. A transfer is between 10.000 and 5.000.000 IDR, and transfers plus gift code purchases are limited to 10.000.000 IDR a day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Start a wallet transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.WalletTransferPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transfer created, waiting for confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, insufficient balance or daily limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipient not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone number belongs to more than one user",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/transfers/{transfer_id}/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Confirms a pending transfer with the sender's password. The balance moves at once, both sides get a ledger entry and the recipient is emailed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Confirm a wallet transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "transfer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sender password",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ConfirmTransferPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transfer completed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseOK"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, insufficient balance or daily limit reached",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized access or invalid password",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Transfer not found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Transfer is no longer pending or has expired",
                         "schema": {
                             "$ref": "#/definitions/entity.ResponseError"
                         }
@@ -467,30 +4534,333 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.BookingRequest": {
+        }
+    },
+    "definitions": {
+        "entity.AssignBookingRoomPayload": {
+            "type": "object",
+            "properties": {
+                "booking_room_id": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BookingRequest": {
+            "type": "object",
+            "properties": {
+                "adults": {
+                    "type": "integer"
+                },
+                "check_in": {
+                    "type": "string"
+                },
+                "check_out": {
+                    "type": "string"
+                },
+                "children": {
+                    "type": "integer"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "room_id": {
+                    "type": "integer"
+                },
+                "room_type_id": {
+                    "type": "integer"
+                },
+                "rooms": {
+                    "description": "books several rooms under one order, takes precedence over room_id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BookingRoomRequest"
+                    }
+                }
+            }
+        },
+        "entity.BookingRoomRequest": {
             "type": "object",
             "properties": {
-                "check_in": {
+                "adults": {
+                    "type": "integer"
+                },
+                "children": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "integer"
+                },
+                "room_type_id": {
+                    "description": "books any room of this type, assigned later by the hotel",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CalendarFeedPayload": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string"
                 },
-                "check_out": {
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CheckInPayload": {
+            "type": "object",
+            "properties": {
+                "balance_payment_method": {
+                    "description": "how an outstanding balance was collected, e.g. \"cash\" or \"card\"",
                     "type": "string"
                 },
-                "room_id": {
+                "id_number": {
+                    "type": "string"
+                },
+                "id_type": {
+                    "description": "e.g. \"ktp\" or \"passport\"",
+                    "type": "string"
+                },
+                "rooms": {
+                    "description": "rooms to put on lines that have none yet",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AssignBookingRoomPayload"
+                    }
+                }
+            }
+        },
+        "entity.CheckOutPayload": {
+            "type": "object",
+            "properties": {
+                "late_checkout_fee": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "description": "how the late checkout fee was collected, e.g. \"cash\" or \"card\"",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ConfirmTransferPayload": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DepositPolicyPayload": {
+            "type": "object",
+            "properties": {
+                "balance_at_desk": {
+                    "type": "boolean"
+                },
+                "balance_due_days": {
+                    "type": "integer"
+                },
+                "deposit_min_nights": {
+                    "type": "integer"
+                },
+                "deposit_percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.GiftCodePayload": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.HotelChargePayload": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "basis": {
+                    "type": "string"
+                },
+                "charge_type": {
+                    "type": "string"
+                },
+                "compound": {
+                    "type": "boolean"
+                },
+                "inclusive": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.HotelContentPayload": {
+            "type": "object",
+            "properties": {
+                "amenities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "check_in_time": {
+                    "type": "string"
+                },
+                "check_out_time": {
+                    "type": "string"
+                },
+                "contact_number": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "star_rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.HotelPhotoOrderPayload": {
+            "type": "object",
+            "properties": {
+                "photo_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.HotelPhotoPayload": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "sort_order": {
                     "type": "integer"
                 }
             }
         },
+        "entity.HousekeepingPayload": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "e.g. why the room is out of order",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"clean\", \"dirty\", \"inspected\" or \"out_of_order\"",
+                    "type": "string"
+                }
+            }
+        },
         "entity.PaymentPayload": {
             "type": "object",
             "properties": {
+                "card_token": {
+                    "description": "Midtrans card token, for credit_card",
+                    "type": "string"
+                },
+                "deposit": {
+                    "description": "pay only the deposit of a booking that offers one",
+                    "type": "boolean"
+                },
                 "order_id": {
                     "type": "string"
                 },
                 "payment_method": {
+                    "description": "\"wallet\", \"bca\", \"bni\", \"bri\", \"cimb\", \"permata\", \"mandiri\", \"qris\", \"gopay\" or \"credit_card\"",
+                    "type": "string"
+                },
+                "use_wallet": {
+                    "description": "pay what the wallet balance covers, the rest with payment_method",
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.PromoCodePayload": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "hotel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "max_discount": {
+                    "type": "number"
+                },
+                "min_nights": {
+                    "type": "integer"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RedeemGiftCodePayload": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 }
             }
@@ -518,6 +4888,99 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ReviewModerationPayload": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "\"approved\" or \"rejected\"",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReviewPayload": {
+            "type": "object",
+            "properties": {
+                "cleanliness_rating": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "location_rating": {
+                    "type": "integer"
+                },
+                "overall_rating": {
+                    "type": "integer"
+                },
+                "service_rating": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "value_rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ReviewReplyPayload": {
+            "type": "object",
+            "properties": {
+                "reply": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RoomTypeAssignPayload": {
+            "type": "object",
+            "properties": {
+                "room_type_id": {
+                    "description": "null unlinks the room",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RoomTypePayload": {
+            "type": "object",
+            "properties": {
+                "amenities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "base_rate": {
+                    "type": "number"
+                },
+                "bed_count": {
+                    "type": "integer"
+                },
+                "bed_type": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "max_adults": {
+                    "type": "integer"
+                },
+                "max_children": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "size_sqm": {
+                    "type": "number"
+                }
+            }
+        },
         "entity.UserLoginPayload": {
             "type": "object",
             "properties": {
@@ -541,6 +5004,10 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
+                "locale": {
+                    "description": "\"id\" (default) or \"en\"",
+                    "type": "string"
+                },
                 "password": {
                     "type": "string"
                 },
@@ -554,8 +5021,40 @@ const docTemplate = `{
             "properties": {
                 "amount": {
                     "type": "number"
+                }
+            }
+        },
+        "entity.WalletTransferPayload": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "email or phone number of the recipient",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.WebhookSubscriptionPayload": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
                 },
-                "bank_transfer": {
+                "url": {
                     "type": "string"
                 }
             }
@@ -3,25 +3,103 @@ package entity
 import "time"
 
 type Booking struct {
-	ID            uint      `gorm:"primaryKey;autoIncrement"`
-	OrderID       string    `gorm:"unique;not null" json:"order_id"`
-	BookingCode   string    `gorm:"type:varchar(10);not null" json:"booking_code"`
-	GuestID       uint      `gorm:"not null" json:"guest_id"`
-	HotelID       uint      `gorm:"not null" json:"hotel_id"`
-	RoomID        uint      `gorm:"not null" json:"room_id"`
-	CheckIn       string    `gorm:"type:date;not null" json:"check_in"`
-	CheckOut      string    `gorm:"type:date;not null" json:"check_out"`
-	TotalDays     int       `gorm:"not null" json:"total_days"`
-	TotalPrice    float64   `gorm:"type:decimal(10,2);not null" json:"total_price"`
-	BookingStatus string    `gorm:"type:varchar(10);default:pending" json:"booking_status"`
-	CreatedAt     time.Time `gorm:"type:timestamp" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"type:timestamp" json:"updated_at"`
+	ID                uint            `gorm:"primaryKey;autoIncrement"`
+	OrderID           string          `gorm:"unique;not null" json:"order_id"`
+	BookingCode       string          `gorm:"type:varchar(10);not null;uniqueIndex:idx_bookings_booking_code" json:"booking_code"`
+	LegacyBookingCode string          `gorm:"type:varchar(30);index" json:"legacy_booking_code,omitempty"` // date based code issued before booking codes were random, still accepted at the desk
+	GuestID           uint            `gorm:"not null" json:"guest_id"`
+	HotelID           uint            `gorm:"not null" json:"hotel_id"`
+	RoomID            uint            `gorm:"not null" json:"room_id"`
+	CheckIn           string          `gorm:"type:date;not null" json:"check_in"`
+	CheckOut          string          `gorm:"type:date;not null" json:"check_out"`
+	TotalDays         int             `gorm:"not null" json:"total_days"`
+	SubTotal          float64         `gorm:"type:decimal(10,2);not null;default:0" json:"sub_total"`
+	PromoCode         string          `gorm:"type:varchar(30)" json:"promo_code,omitempty"`
+	Discount          float64         `gorm:"type:decimal(10,2);not null;default:0" json:"discount"`
+	TotalPrice        float64         `gorm:"type:decimal(10,2);not null" json:"total_price"`
+	DepositPercent    int             `gorm:"not null;default:0" json:"deposit_percent"` // hotel policy when booked, 0 when paid in full
+	DepositAmount     float64         `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_amount"`
+	AmountPaid        float64         `gorm:"type:decimal(10,2);not null;default:0" json:"amount_paid"`
+	BalanceDueDate    *time.Time      `gorm:"type:date" json:"balance_due_date"` // nil when the balance is paid at check-in
+	BalanceRemindedAt *time.Time      `gorm:"type:timestamp" json:"-"`
+	BookingStatus     string          `gorm:"type:varchar(12);default:pending" json:"booking_status"` // "pending", "settlement", "checked_in", "checked_out", "no_show", "expire" or "cancel"
+	GuestIDType       string          `gorm:"type:varchar(20)" json:"guest_id_type,omitempty"`
+	GuestIDNumber     string          `gorm:"type:varchar(50)" json:"guest_id_number,omitempty"`
+	CheckedInAt       *time.Time      `gorm:"type:timestamp" json:"checked_in_at"`
+	CheckedInBy       uint            `gorm:"not null;default:0" json:"checked_in_by,omitempty"` // staff user who checked the guest in
+	CheckedOutAt      *time.Time      `gorm:"type:timestamp" json:"checked_out_at"`
+	LateCheckoutFee   float64         `gorm:"type:decimal(10,2);not null;default:0" json:"late_checkout_fee"`
+	Rooms             []BookingRoom   `gorm:"foreignKey:BookingID" json:"rooms,omitempty"`
+	Charges           []BookingCharge `gorm:"foreignKey:BookingID" json:"charges,omitempty"`
+	CreatedAt         time.Time       `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt         time.Time       `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type BookingRoom struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BookingID    uint      `gorm:"not null;index" json:"-"`
+	RoomID       uint      `gorm:"not null" json:"room_id"` // 0 until a room is assigned to a room type booking
+	RoomTypeID   *uint     `json:"room_type_id"`
+	RoomNumber   string    `gorm:"type:varchar(10);not null" json:"room_number"`
+	RoomTypeName string    `gorm:"type:varchar(50)" json:"room_type_name,omitempty"` // as booked, names lines that have no room yet
+	Adults       int       `gorm:"not null" json:"adults"`
+	Children     int       `gorm:"not null;default:0" json:"children"`
+	RoomRate     float64   `gorm:"type:decimal(10,2);not null" json:"room_rate"`
+	SubTotal     float64   `gorm:"type:decimal(10,2);not null" json:"sub_total"`
+	Status       string    `gorm:"type:varchar(10);not null;default:active" json:"status"` // "active" or "cancelled"
+	RefundAmount float64   `gorm:"type:decimal(10,2);not null;default:0" json:"refund_amount"`
+	UpdatedAt    time.Time `gorm:"type:timestamp" json:"-"`
 }
 
 type BookingRequest struct {
-	RoomID   uint   `json:"room_id"`
-	CheckIn  string `json:"check_in"`
-	CheckOut string `json:"check_out"`
+	RoomID     uint                 `json:"room_id"`
+	RoomTypeID uint                 `json:"room_type_id"`
+	Adults     int                  `json:"adults"`
+	Children   int                  `json:"children"`
+	Rooms      []BookingRoomRequest `json:"rooms"` // books several rooms under one order, takes precedence over room_id
+	CheckIn    string               `json:"check_in"`
+	CheckOut   string               `json:"check_out"`
+	PromoCode  string               `json:"promo_code"`
+}
+
+type BookingRoomRequest struct {
+	RoomID     uint `json:"room_id"`
+	RoomTypeID uint `json:"room_type_id"` // books any room of this type, assigned later by the hotel
+	Adults     int  `json:"adults"`
+	Children   int  `json:"children"`
+}
+
+type AssignBookingRoomPayload struct {
+	BookingRoomID uint `json:"booking_room_id"`
+	RoomID        uint `json:"room_id"`
+}
+
+type CheckInPayload struct {
+	IDType               string                     `json:"id_type"` // e.g. "ktp" or "passport"
+	IDNumber             string                     `json:"id_number"`
+	Rooms                []AssignBookingRoomPayload `json:"rooms"`                  // rooms to put on lines that have none yet
+	BalancePaymentMethod string                     `json:"balance_payment_method"` // how an outstanding balance was collected, e.g. "cash" or "card"
+}
+
+type CheckOutPayload struct {
+	LateCheckoutFee float64 `json:"late_checkout_fee"`
+	PaymentMethod   string  `json:"payment_method"` // how the late checkout fee was collected, e.g. "cash" or "card"
+}
+
+type BookingQuote struct {
+	HotelID        uint            `json:"hotel_id"`
+	Rooms          []BookingRoom   `json:"rooms"`
+	CheckIn        string          `json:"check_in"`
+	CheckOut       string          `json:"check_out"`
+	TotalDays      int             `json:"total_days"`
+	SubTotal       float64         `json:"sub_total"`
+	PromoCode      string          `json:"promo_code,omitempty"`
+	Discount       float64         `json:"discount"`
+	Charges        []BookingCharge `json:"charges"`
+	TotalPrice     float64         `json:"total_price"`
+	DepositPercent int             `json:"deposit_percent"` // 0 when the stay must be paid in full
+	DepositAmount  float64         `json:"deposit_amount"`
+	BalanceDueDate string          `json:"balance_due_date,omitempty"` // empty when the balance is paid at check-in
 }
 
 type BookingHistoryResponse struct {
@@ -36,3 +114,62 @@ type BookingHistoryResponse struct {
 	BookingDate   string  `json:"booking_date"`
 	BookingStatus string  `json:"booking_status"`
 }
+
+type BookingDetailResponse struct {
+	OrderID        string                 `json:"order_id"`
+	BookingCode    string                 `json:"booking_code"`
+	BookingStatus  string                 `json:"booking_status"`
+	CheckIn        string                 `json:"check_in"`
+	CheckOut       string                 `json:"check_out"`
+	TotalDays      int                    `json:"total_days"`
+	Hotel          BookingHotelDetail     `json:"hotel"`
+	Guest          BookingGuestDetail     `json:"guest"`
+	Rooms          []BookingRoom          `json:"rooms"`
+	SubTotal       float64                `json:"sub_total"`
+	PromoCode      string                 `json:"promo_code,omitempty"`
+	Discount       float64                `json:"discount"`
+	Charges        []BookingCharge        `json:"charges"`
+	TotalPrice     float64                `json:"total_price"`
+	DepositAmount  float64                `json:"deposit_amount"`
+	AmountPaid     float64                `json:"amount_paid"`
+	BalanceDue     float64                `json:"balance_due"`
+	BalanceDueDate *time.Time             `json:"balance_due_date"`
+	Payment        *BookingPaymentDetail  `json:"payment"`  // nil until a payment is started
+	Payments       []BookingPaymentDetail `json:"payments"` // newest first
+	Timeline       []BookingTimelineEvent `json:"timeline"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+type BookingHotelDetail struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Location      string `json:"location"`
+	ContactNumber string `json:"contact_number"`
+	Email         string `json:"email"`
+	CheckInTime   string `json:"check_in_time"`
+	CheckOutTime  string `json:"check_out_time"`
+}
+
+type BookingGuestDetail struct {
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type BookingPaymentDetail struct {
+	PaymentID     string     `json:"payment_id"`
+	PaymentMethod string     `json:"payment_method"`
+	Bank          string     `json:"bank,omitempty"`
+	VANumber      string     `json:"va_number,omitempty"`
+	PaymentStatus string     `json:"payment_status"`
+	Installment   string     `json:"installment"`
+	Amount        float64    `json:"amount"`
+	PaymentDate   *time.Time `json:"payment_date"`
+}
+
+type BookingTimelineEvent struct {
+	Event       string    `json:"event"` // "booked", "payment_started", "paid", "deposit_paid", "room_cancelled", "checked_in", "checked_out", "no_show", "expired" or "cancelled"
+	Description string    `json:"description"`
+	Time        time.Time `json:"time"`
+}
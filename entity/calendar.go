@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// CalendarFeed is an external ICS calendar, e.g. from another OTA, whose
+// events block a room here
+type CalendarFeed struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	RoomID       uint       `gorm:"not null;index" json:"room_id"`
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	Source       string     `gorm:"type:varchar(255);not null" json:"source"` // http(s) URL or local file path
+	Active       bool       `gorm:"not null" json:"active"`
+	LastSyncedAt *time.Time `gorm:"type:timestamp" json:"last_synced_at"`
+	LastError    string     `gorm:"type:text" json:"last_error"`
+	CreatedAt    time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+// AvailabilityBlock closes a room for a date range. End date is exclusive,
+// like a booking's check-out.
+type AvailabilityBlock struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	RoomID    uint      `gorm:"not null;index" json:"room_id"`
+	FeedID    uint      `gorm:"not null;uniqueIndex:idx_availability_blocks_feed_uid" json:"feed_id"`
+	UID       string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_availability_blocks_feed_uid" json:"uid"` // UID of the imported event
+	StartDate string    `gorm:"type:date;not null" json:"start_date"`
+	EndDate   string    `gorm:"type:date;not null" json:"end_date"`
+	Summary   string    `gorm:"type:varchar(255)" json:"summary"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type CalendarFeedPayload struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Active *bool  `json:"active"`
+}
+
+type CalendarExportResponse struct {
+	RoomID uint   `json:"room_id"`
+	URL    string `json:"url"` // public feed to give to other OTAs
+}
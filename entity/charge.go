@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type HotelCharge struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	HotelID    uint      `gorm:"not null;index" json:"hotel_id"`
+	Name       string    `gorm:"type:varchar(50);not null" json:"name"`
+	ChargeType string    `gorm:"type:varchar(10);not null" json:"charge_type"`   // "percentage" or "fixed"
+	Amount     float64   `gorm:"type:decimal(10,2);not null" json:"amount"`      // percent for "percentage", IDR for "fixed"
+	Basis      string    `gorm:"type:varchar(10);default:per_stay" json:"basis"` // "per_night" or "per_stay", fixed charges only
+	Inclusive  bool      `gorm:"default:false" json:"inclusive"`                 // already contained in the room rate
+	Compound   bool      `gorm:"default:false" json:"compound"`                  // applied on top of earlier exclusive charges
+	SortOrder  int       `gorm:"default:0" json:"sort_order"`
+	Active     bool      `gorm:"not null" json:"active"`
+	CreatedAt  time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type BookingCharge struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement" json:"-"`
+	BookingID     uint    `gorm:"not null;index" json:"-"`
+	HotelChargeID uint    `gorm:"not null" json:"hotel_charge_id"`
+	Name          string  `gorm:"type:varchar(50);not null" json:"name"`
+	ChargeType    string  `gorm:"type:varchar(10);not null" json:"charge_type"`
+	Rate          float64 `gorm:"type:decimal(10,2);not null" json:"rate"`
+	Basis         string  `gorm:"type:varchar(10);default:per_stay" json:"basis"`
+	Inclusive     bool    `gorm:"default:false" json:"inclusive"`
+	Compound      bool    `gorm:"default:false" json:"compound"`
+	Amount        float64 `gorm:"type:decimal(10,2);not null" json:"amount"`
+}
+
+type HotelChargePayload struct {
+	Name       string  `json:"name"`
+	ChargeType string  `json:"charge_type"`
+	Amount     float64 `json:"amount"`
+	Basis      string  `json:"basis"`
+	Inclusive  bool    `json:"inclusive"`
+	Compound   bool    `json:"compound"`
+	SortOrder  int     `json:"sort_order"`
+	Active     *bool   `json:"active"`
+}
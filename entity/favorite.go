@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type Favorite struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_favorites_user_hotel" json:"user_id"`
+	HotelID   uint      `gorm:"not null;uniqueIndex:idx_favorites_user_hotel" json:"hotel_id"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+}
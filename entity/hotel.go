@@ -1,18 +1,102 @@
 package entity
 
+import "time"
+
 type Hotel struct {
-	ID            uint   `gorm:"primaryKey;autoIncrement"`
-	Name          string `gorm:"type:varchar(100);not null" json:"name"`
-	Location      string `gorm:"type:varchar(255);not null" json:"location"`
-	ContactNumber string `gorm:"type:varchar(15)" json:"contact_number"`
-	Email         string `gorm:"type:varchar(100)" json:"email"`
-	Rooms         []Room `gorm:"foreignKey:HotelID" json:"rooms"`
+	ID               uint         `gorm:"primaryKey;autoIncrement"`
+	Name             string       `gorm:"type:varchar(100);not null" json:"name"`
+	Location         string       `gorm:"type:varchar(255);not null" json:"location"`
+	ContactNumber    string       `gorm:"type:varchar(15)" json:"contact_number"`
+	Email            string       `gorm:"type:varchar(100)" json:"email"`
+	Description      string       `gorm:"type:text" json:"description"`
+	StarRating       int          `gorm:"not null;default:0" json:"star_rating"`
+	AverageRating    float64      `gorm:"type:decimal(3,2);not null;default:0" json:"average_rating"` // of approved reviews
+	ReviewCount      int          `gorm:"not null;default:0" json:"review_count"`
+	CheckInTime      string       `gorm:"type:varchar(5);default:'14:00'" json:"check_in_time"`
+	CheckOutTime     string       `gorm:"type:varchar(5);default:'12:00'" json:"check_out_time"`
+	DepositPercent   int          `gorm:"not null;default:0" json:"deposit_percent"`     // share of the total paid at booking, 0 requires full payment
+	DepositMinNights int          `gorm:"not null;default:0" json:"deposit_min_nights"`  // shortest stay a deposit is offered for
+	BalanceDueDays   int          `gorm:"not null;default:0" json:"balance_due_days"`    // days before check-in the balance is due
+	BalanceAtDesk    bool         `gorm:"not null;default:false" json:"balance_at_desk"` // balance is paid at check-in instead
+	Amenities        []string     `gorm:"type:text;serializer:json" json:"amenities"`
+	Latitude         *float64     `gorm:"type:decimal(9,6)" json:"latitude"`
+	Longitude        *float64     `gorm:"type:decimal(9,6)" json:"longitude"`
+	Photos           []HotelPhoto `gorm:"foreignKey:HotelID" json:"photos"`
+	Rooms            []Room       `gorm:"foreignKey:HotelID" json:"rooms"`
+	RoomTypes        []RoomType   `gorm:"foreignKey:HotelID" json:"room_types"`
+	IsFavorite       bool         `gorm:"-" json:"is_favorite"` // for the logged-in user
+}
+
+type HotelPhoto struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	HotelID      uint      `gorm:"not null;index" json:"hotel_id"`
+	URL          string    `gorm:"type:varchar(255);not null" json:"url"`
+	ThumbnailURL string    `gorm:"type:varchar(255);not null" json:"thumbnail_url"`
+	Caption      string    `gorm:"type:varchar(255)" json:"caption"`
+	SortOrder    int       `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt    time.Time `gorm:"type:timestamp" json:"created_at"`
 }
 
 type GetHotelList struct {
-	ID             uint   `json:"id"`
-	Name           string `json:"name"`
-	Location       string `json:"location"`
-	Price          string `json:"price"`
-	AvailableRooms int    `json:"available_rooms"`
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	Location       string   `json:"location"`
+	StarRating     int      `json:"star_rating"`
+	AverageRating  float64  `json:"average_rating"`
+	ReviewCount    int      `json:"review_count"`
+	Price          string   `json:"price"`
+	AvailableRooms int      `json:"available_rooms"`
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	Distance       *float64 `json:"distance_km,omitempty"`
+	IsFavorite     bool     `json:"is_favorite" gorm:"-"`
+}
+
+type HotelListFilter struct {
+	Latitude  *float64 `query:"lat"`
+	Longitude *float64 `query:"lng"`
+	RadiusKm  float64  `query:"radius_km"`
+	MinPrice  float64  `query:"min_price"`
+	MaxPrice  float64  `query:"max_price"`
+	Sort      string   `query:"sort"` // "distance", "price_asc", "price_desc" or "rating"
+	UserID    uint     `query:"-"`    // logged-in user, 0 when anonymous
+}
+
+type HotelSearchResult struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Location   string  `json:"location"`
+	StarRating int     `json:"star_rating"`
+	Rank       float64 `json:"rank"`
+	Snippet    string  `json:"snippet"` // matched terms wrapped in <mark></mark>
+}
+
+type HotelContentPayload struct {
+	Name          string   `json:"name"`
+	Location      string   `json:"location"`
+	ContactNumber string   `json:"contact_number"`
+	Email         string   `json:"email"`
+	Description   string   `json:"description"`
+	StarRating    int      `json:"star_rating"`
+	CheckInTime   string   `json:"check_in_time"`
+	CheckOutTime  string   `json:"check_out_time"`
+	Amenities     []string `json:"amenities"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+}
+
+type DepositPolicyPayload struct {
+	DepositPercent   int  `json:"deposit_percent"`
+	DepositMinNights int  `json:"deposit_min_nights"`
+	BalanceDueDays   int  `json:"balance_due_days"`
+	BalanceAtDesk    bool `json:"balance_at_desk"`
+}
+
+type HotelPhotoPayload struct {
+	Caption   string `json:"caption" form:"caption"`
+	SortOrder int    `json:"sort_order" form:"sort_order"`
+}
+
+type HotelPhotoOrderPayload struct {
+	PhotoIDs []uint `json:"photo_ids"`
 }
@@ -0,0 +1,25 @@
+package entity
+
+type HousekeepingPayload struct {
+	Status string `json:"status"` // "clean", "dirty", "inspected" or "out_of_order"
+	Note   string `json:"note"`   // e.g. why the room is out of order
+}
+
+// HousekeepingTask is one room on a hotel's daily housekeeping list
+type HousekeepingTask struct {
+	RoomID             uint   `json:"room_id"`
+	RoomNumber         string `json:"room_number"`
+	HousekeepingStatus string `json:"housekeeping_status"`
+	Note               string `json:"note,omitempty"`
+	Occupied           bool   `json:"occupied"`
+	Arrival            bool   `json:"arrival"`   // a guest checks in that day
+	Departure          bool   `json:"departure"` // the guest checks out that day
+	Task               string `json:"task"`      // "clean", "service", "inspect" or "maintenance"
+	Priority           string `json:"priority"`  // "high" when a guest arrives that day, else "normal"
+}
+
+type HousekeepingTaskList struct {
+	HotelID uint               `json:"hotel_id"`
+	Date    string             `json:"date"`
+	Tasks   []HousekeepingTask `json:"tasks"`
+}
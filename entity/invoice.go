@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+type Invoice struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	InvoiceNumber string    `gorm:"type:varchar(30);unique;not null" json:"invoice_number"`
+	HotelID       uint      `gorm:"not null;uniqueIndex:idx_invoices_hotel_sequence" json:"hotel_id"`
+	Sequence      int       `gorm:"not null;uniqueIndex:idx_invoices_hotel_sequence" json:"sequence"` // counts up per hotel
+	BookingID     uint      `gorm:"not null;unique" json:"booking_id"`
+	OrderID       string    `gorm:"not null" json:"order_id"`
+	IssuedAt      time.Time `gorm:"type:timestamp;not null" json:"issued_at"`
+	// Lines and totals as invoiced, later changes to the booking do not alter the invoice
+	Rooms      []BookingRoom   `gorm:"type:text;serializer:json" json:"rooms"`
+	Charges    []BookingCharge `gorm:"type:text;serializer:json" json:"charges"`
+	SubTotal   float64         `gorm:"type:decimal(10,2);not null;default:0" json:"sub_total"`
+	PromoCode  string          `gorm:"type:varchar(30)" json:"promo_code,omitempty"`
+	Discount   float64         `gorm:"type:decimal(10,2);not null;default:0" json:"discount"`
+	TotalPrice float64         `gorm:"type:decimal(10,2);not null;default:0" json:"total_price"`
+	CreatedAt  time.Time       `gorm:"type:timestamp" json:"created_at"`
+}
+
+// InvoiceDocument is everything printed on an invoice
+type InvoiceDocument struct {
+	Invoice  Invoice
+	Booking  Booking
+	Hotel    Hotel
+	Guest    User
+	Payments []Payment // settled, oldest first; a deposit and its balance, or both parts of a split payment, are separate
+}
@@ -17,6 +17,18 @@ type MidtransResponse struct {
 		Bank     string `json:"bank"`
 		VANumber string `json:"va_number"`
 	} `json:"va_numbers"`
+	PermataVANumber string           `json:"permata_va_number"`
+	BillKey         string           `json:"bill_key"`    // Mandiri bill payment
+	BillerCode      string           `json:"biller_code"` // Mandiri bill payment
+	QRString        string           `json:"qr_string"`
+	Actions         []MidtransAction `json:"actions"`      // QR image and deeplink of QRIS and GoPay
+	RedirectURL     string           `json:"redirect_url"` // 3DS page of credit card payments
+}
+
+type MidtransAction struct {
+	Name   string `json:"name"` // e.g. "generate-qr-code" or "deeplink-redirect"
+	Method string `json:"method"`
+	URL    string `json:"url"`
 }
 
 type MidtransPaymentPayload struct {
@@ -31,15 +43,31 @@ type MidtransPaymentPayload struct {
 		LastName  string `json:"last_name"`
 		Phone     string `json:"phone"`
 	} `json:"customer_details"`
-	ItemDetails []struct {
-		ID       string `json:"id"`
-		Price    string `json:"price"`
-		Quantity int    `json:"quantity"`
-		Name     string `json:"name"`
-	} `json:"item_details"`
-	BankTransfer struct {
-		Bank string `json:"bank"`
-	} `json:"bank_transfer"`
+	ItemDetails  []MidtransItemDetail  `json:"item_details"`
+	BankTransfer *MidtransBankTransfer `json:"bank_transfer,omitempty"`
+	Echannel     *MidtransEchannel     `json:"echannel,omitempty"`
+	CreditCard   *MidtransCreditCard   `json:"credit_card,omitempty"`
+}
+
+type MidtransBankTransfer struct {
+	Bank string `json:"bank"`
+}
+
+type MidtransEchannel struct {
+	BillInfo1 string `json:"bill_info1"`
+	BillInfo2 string `json:"bill_info2"`
+}
+
+type MidtransCreditCard struct {
+	TokenID        string `json:"token_id"`
+	Authentication bool   `json:"authentication"` // asks for 3DS
+}
+
+type MidtransItemDetail struct {
+	ID       string `json:"id"`
+	Price    string `json:"price"`
+	Quantity int    `json:"quantity"`
+	Name     string `json:"name"`
 }
 
 type MidtransCallbackResponse struct {
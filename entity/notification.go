@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Notification is an email waiting in the outbox. It is written in the same
+// transaction as the change it reports and delivered later by the worker.
+type Notification struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	Recipient     string     `gorm:"type:varchar(100);not null" json:"recipient"`
+	Template      string     `gorm:"type:varchar(30);not null" json:"template"`
+	Locale        string     `gorm:"type:varchar(2);not null" json:"locale"`
+	Subject       string     `gorm:"type:varchar(255);not null" json:"subject"`
+	TextBody      string     `gorm:"type:text;not null" json:"text_body"`
+	HTMLBody      string     `gorm:"type:text;not null" json:"html_body"`
+	Status        string     `gorm:"type:varchar(10);not null;default:pending;index:idx_notifications_status_next" json:"status"` // "pending", "sent" or "failed"
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp;not null;index:idx_notifications_status_next" json:"next_attempt_at"`
+	SentAt        *time.Time `gorm:"type:timestamp" json:"sent_at"`
+	CreatedAt     time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type Email struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
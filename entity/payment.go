@@ -5,27 +5,48 @@ import "time"
 type Payment struct {
 	ID              uint       `gorm:"primaryKey;autoIncrement"`
 	PaymentID       string     `gorm:"unique;not null" json:"payment_id"`
-	OrderID         string     `gorm:"unique;not null" json:"order_id"`
+	OrderID         string     `gorm:"not null;index" json:"order_id"`
+	MidtransOrderID string     `gorm:"type:varchar(50);uniqueIndex:idx_payments_midtrans_order_id,where:midtrans_order_id <> ''" json:"midtrans_order_id,omitempty"` // order_id sent to Midtrans for this attempt
 	UserID          uint       `gorm:"not null" json:"user_id"`
 	TotalAmount     float64    `gorm:"type:decimal(10,2);not null" json:"total_amount"`
-	TransactionType string     `gorm:"type:varchar(20);not null" json:"transaction_type"` // "topup" or "booking"
+	Fee             float64    `gorm:"type:decimal(10,2);not null;default:0" json:"fee"`          // payment method fee, part of TotalAmount
+	TransactionType string     `gorm:"type:varchar(20);not null" json:"transaction_type"`         // "topup" or "booking"
+	Installment     string     `gorm:"type:varchar(10);not null;default:full" json:"installment"` // "full", "deposit" or "balance"
 	PaymentDate     *time.Time `gorm:"type:date" json:"payment_date"`
-	PaymentStatus   string     `gorm:"type:varchar(10);not null" json:"payment_status"`
-	PaymentMethod   string     `gorm:"type:varchar(20);not null" json:"payment_method"`
+	PaymentStatus   string     `gorm:"type:varchar(10);not null" json:"payment_status"` // Midtrans status, "held" or "released" for the wallet part of a split payment
+	PaymentMethod   string     `gorm:"type:varchar(30);not null" json:"payment_method"`
+	Bank            string     `gorm:"type:varchar(20)" json:"bank,omitempty"`
+	VANumber        string     `gorm:"type:varchar(30)" json:"va_number,omitempty"`
+	BillerCode      string     `gorm:"type:varchar(10)" json:"biller_code,omitempty"`    // with VANumber holding the bill key
+	WalletHoldID    string     `gorm:"type:varchar(50)" json:"wallet_hold_id,omitempty"` // payment_id of the wallet part held for this attempt
+	ReconciledAt    *time.Time `gorm:"type:timestamp" json:"-"`                          // last status lookup of a pending payment at Midtrans
 	CreatedAt       time.Time  `gorm:"type:timestamp" json:"created_at"`
 	UpdatedAt       time.Time  `gorm:"type:timestamp" json:"updated_at"`
 }
 
 type PaymentPayload struct {
 	OrderID       string `json:"order_id"`
-	PaymentMethod string `json:"payment_method"`
+	PaymentMethod string `json:"payment_method"` // "wallet", "bca", "bni", "bri", "cimb", "permata", "mandiri", "qris", "gopay" or "credit_card"
+	CardToken     string `json:"card_token"`     // Midtrans card token, for credit_card
+	Deposit       bool   `json:"deposit"`        // pay only the deposit of a booking that offers one
+	UseWallet     bool   `json:"use_wallet"`     // pay what the wallet balance covers, the rest with payment_method
 }
 
 type PaymentResponse struct {
 	TransactionID     string  `json:"transaction_id"`
 	TransactionStatus string  `json:"transaction_status"`
 	Amount            float64 `json:"amount"`
+	Fee               float64 `json:"fee"`
+	WalletAmount      float64 `json:"wallet_amount,omitempty"` // held from the wallet balance until the rest is paid
 	PaymentType       string  `json:"payment_type"`
+	Installment       string  `json:"installment,omitempty"`
 	Bank              string  `json:"bank,omitempty"`
 	VANumber          string  `json:"va_number,omitempty"`
+	BillKey           string  `json:"bill_key,omitempty"`
+	BillerCode        string  `json:"biller_code,omitempty"`
+	QRString          string  `json:"qr_string,omitempty"`
+	QRCodeURL         string  `json:"qr_code_url,omitempty"`
+	DeeplinkURL       string  `json:"deeplink_url,omitempty"`
+	RedirectURL       string  `json:"redirect_url,omitempty"` // 3DS page to send the guest to
+	MidtransOrderID   string  `json:"midtrans_order_id,omitempty"`
 }
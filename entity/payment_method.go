@@ -0,0 +1,14 @@
+package entity
+
+// PaymentMethod is one entry of the payment method catalog. Fees are added on
+// top of the amount due.
+type PaymentMethod struct {
+	Code       string  `json:"code"` // sent as payment_method
+	Name       string  `json:"name"`
+	Type       string  `json:"type"` // "wallet", "bank_transfer", "echannel", "qris", "gopay" or "credit_card"
+	LogoURL    string  `json:"logo_url"`
+	FeeFlat    float64 `json:"fee_flat"`
+	FeePercent float64 `json:"fee_percent"`
+	MinAmount  float64 `json:"min_amount"`
+	MaxAmount  float64 `json:"max_amount"` // 0 means no limit
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type PromoCode struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code          string     `gorm:"type:varchar(30);unique;not null" json:"code"`
+	Description   string     `gorm:"type:varchar(255)" json:"description"`
+	DiscountType  string     `gorm:"type:varchar(10);not null" json:"discount_type"`    // "percentage" or "fixed"
+	DiscountValue float64    `gorm:"type:decimal(10,2);not null" json:"discount_value"` // percent for "percentage", IDR for "fixed"
+	MaxDiscount   float64    `gorm:"type:decimal(10,2);default:0" json:"max_discount"`  // cap for percentage discounts, 0 means no cap
+	ValidFrom     *time.Time `gorm:"type:timestamp" json:"valid_from"`
+	ValidUntil    *time.Time `gorm:"type:timestamp" json:"valid_until"`
+	UsageLimit    int        `gorm:"default:0" json:"usage_limit"`    // 0 means unlimited
+	PerUserLimit  int        `gorm:"default:0" json:"per_user_limit"` // 0 means unlimited
+	MinNights     int        `gorm:"default:0" json:"min_nights"`
+	Hotels        []Hotel    `gorm:"many2many:promo_code_hotels" json:"hotels,omitempty"` // empty means every hotel
+	Active        bool       `gorm:"not null" json:"active"`
+	CreatedAt     time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type PromoRedemption struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PromoCodeID    uint      `gorm:"not null;index" json:"promo_code_id"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	BookingID      uint      `gorm:"not null" json:"booking_id"`
+	OrderID        string    `gorm:"not null;index" json:"order_id"`
+	DiscountAmount float64   `gorm:"type:decimal(10,2);not null" json:"discount_amount"`
+	Status         string    `gorm:"type:varchar(10);not null" json:"status"` // "held", "redeemed" or "released"
+	CreatedAt      time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type PromoCodePayload struct {
+	Code          string     `json:"code"`
+	Description   string     `json:"description"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue float64    `json:"discount_value"`
+	MaxDiscount   float64    `json:"max_discount"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until"`
+	UsageLimit    int        `json:"usage_limit"`
+	PerUserLimit  int        `json:"per_user_limit"`
+	MinNights     int        `json:"min_nights"`
+	HotelIDs      []uint     `json:"hotel_ids"`
+	Active        *bool      `json:"active"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// PaymentDiscrepancy is something reconciliation found between a payment and
+// its Midtrans transaction
+type PaymentDiscrepancy struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	PaymentID       string    `gorm:"type:varchar(50);not null;index" json:"payment_id"`
+	OrderID         string    `gorm:"type:varchar(100);not null" json:"order_id"`
+	MidtransOrderID string    `gorm:"type:varchar(50)" json:"midtrans_order_id"`
+	Kind            string    `gorm:"type:varchar(30);not null" json:"kind"` // "settled_at_gateway", "amount_mismatch", "duplicate_payment" or "not_found_at_gateway"
+	LocalStatus     string    `gorm:"type:varchar(20)" json:"local_status"`
+	GatewayStatus   string    `gorm:"type:varchar(20)" json:"gateway_status"`
+	LocalAmount     float64   `gorm:"type:decimal(10,2);not null;default:0" json:"local_amount"`
+	GatewayAmount   float64   `gorm:"type:decimal(10,2);not null;default:0" json:"gateway_amount"`
+	Detail          string    `gorm:"type:text" json:"detail"`
+	Resolved        bool      `gorm:"not null" json:"resolved"`
+	CreatedAt       time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type ReconciliationReport struct {
+	StartedAt     time.Time            `json:"started_at"`
+	Checked       int                  `json:"checked"` // stale pending payments looked up at Midtrans
+	Updated       int                  `json:"updated"` // payments whose status changed
+	Discrepancies []PaymentDiscrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+type Review struct {
+	ID                uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	BookingID         uint       `gorm:"not null;unique" json:"-"`
+	OrderID           string     `gorm:"not null" json:"order_id"`
+	HotelID           uint       `gorm:"not null;index" json:"hotel_id"`
+	UserID            uint       `gorm:"not null" json:"user_id"`
+	GuestName         string     `gorm:"type:varchar(100)" json:"guest_name"`
+	OverallRating     int        `gorm:"not null" json:"overall_rating"`
+	CleanlinessRating int        `gorm:"not null;default:0" json:"cleanliness_rating"` // 0 means not rated
+	ServiceRating     int        `gorm:"not null;default:0" json:"service_rating"`
+	LocationRating    int        `gorm:"not null;default:0" json:"location_rating"`
+	ValueRating       int        `gorm:"not null;default:0" json:"value_rating"`
+	Title             string     `gorm:"type:varchar(100)" json:"title"`
+	Comment           string     `gorm:"type:text" json:"comment"`
+	Status            string     `gorm:"type:varchar(10);not null;default:pending" json:"status"` // "pending", "approved" or "rejected"
+	HotelReply        string     `gorm:"type:text" json:"hotel_reply"`
+	RepliedAt         *time.Time `gorm:"type:timestamp" json:"replied_at"`
+	CreatedAt         time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type ReviewPayload struct {
+	OverallRating     int    `json:"overall_rating"`
+	CleanlinessRating int    `json:"cleanliness_rating"`
+	ServiceRating     int    `json:"service_rating"`
+	LocationRating    int    `json:"location_rating"`
+	ValueRating       int    `json:"value_rating"`
+	Title             string `json:"title"`
+	Comment           string `json:"comment"`
+}
+
+type ReviewModerationPayload struct {
+	Status string `json:"status"` // "approved" or "rejected"
+}
+
+type ReviewReplyPayload struct {
+	Reply string `json:"reply"`
+}
+
+type ReviewSummary struct {
+	ReviewCount       int     `json:"review_count"`
+	OverallRating     float64 `json:"overall_rating"`
+	CleanlinessRating float64 `json:"cleanliness_rating"`
+	ServiceRating     float64 `json:"service_rating"`
+	LocationRating    float64 `json:"location_rating"`
+	ValueRating       float64 `json:"value_rating"`
+}
+
+type HotelReviewsResponse struct {
+	Summary ReviewSummary `json:"summary"`
+	Reviews []Review      `json:"reviews"`
+}
@@ -1,10 +1,54 @@
 package entity
 
+import "time"
+
 type Room struct {
-	ID         uint    `gorm:"primaryKey;autoIncrement"`
-	HotelID    uint    `gorm:"not null" json:"hotel_id"`
-	RoomNumber string  `gorm:"type:varchar(10);not null" json:"room_number"`
-	RoomType   string  `gorm:"type:varchar(20);not null" json:"room_type"`
-	Price      float64 `gorm:"type:decimal(10,2);not null" json:"price"`
-	Status     string  `gorm:"type:varchar(10);not null" json:"status"`
+	ID                    uint       `gorm:"primaryKey;autoIncrement"`
+	HotelID               uint       `gorm:"not null" json:"hotel_id"`
+	RoomTypeID            *uint      `gorm:"index" json:"room_type_id"`
+	RoomNumber            string     `gorm:"type:varchar(10);not null" json:"room_number"`
+	RoomType              string     `gorm:"type:varchar(20);not null" json:"room_type"`
+	Price                 float64    `gorm:"type:decimal(10,2);not null" json:"price"`
+	Status                string     `gorm:"type:varchar(10);not null" json:"status"`                            // "Available" or "occupied" while a guest is checked in
+	HousekeepingStatus    string     `gorm:"type:varchar(12);not null;default:clean" json:"housekeeping_status"` // "clean", "dirty", "inspected" or "out_of_order"
+	HousekeepingNote      string     `gorm:"type:varchar(255)" json:"housekeeping_note,omitempty"`
+	HousekeepingUpdatedAt *time.Time `gorm:"type:timestamp" json:"housekeeping_updated_at"`
+	MaxAdults             int        `gorm:"not null;default:2" json:"max_adults"`   // used when the room has no room type
+	MaxChildren           int        `gorm:"not null;default:1" json:"max_children"` // used when the room has no room type
+	Type                  *RoomType  `gorm:"foreignKey:RoomTypeID" json:"type,omitempty"`
+	CalendarToken         string     `gorm:"type:varchar(36)" json:"-"` // grants access to the room's ICS feed
+}
+
+type RoomType struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	HotelID     uint      `gorm:"not null;index" json:"hotel_id"`
+	Name        string    `gorm:"type:varchar(50);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	MaxAdults   int       `gorm:"not null" json:"max_adults"`
+	MaxChildren int       `gorm:"not null;default:0" json:"max_children"`
+	BedType     string    `gorm:"type:varchar(20)" json:"bed_type"` // e.g. "king", "queen", "twin"
+	BedCount    int       `gorm:"not null;default:1" json:"bed_count"`
+	SizeSqm     float64   `gorm:"type:decimal(6,2)" json:"size_sqm"`
+	Amenities   []string  `gorm:"type:text;serializer:json" json:"amenities"`
+	Photos      []string  `gorm:"type:text;serializer:json" json:"photos"`
+	BaseRate    float64   `gorm:"type:decimal(10,2);not null" json:"base_rate"`
+	CreatedAt   time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type RoomTypePayload struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	MaxAdults   int      `json:"max_adults"`
+	MaxChildren int      `json:"max_children"`
+	BedType     string   `json:"bed_type"`
+	BedCount    int      `json:"bed_count"`
+	SizeSqm     float64  `json:"size_sqm"`
+	Amenities   []string `json:"amenities"`
+	Photos      []string `json:"photos"`
+	BaseRate    float64  `json:"base_rate"`
+}
+
+type RoomTypeAssignPayload struct {
+	RoomTypeID *uint `json:"room_type_id"` // null unlinks the room
 }
@@ -10,6 +10,8 @@ type User struct {
 	Password    string    `gorm:"type:varchar(255);not null" json:"-"`
 	PhoneNumber string    `gorm:"type:varchar(15)" json:"phone_number"`
 	Balance     float64   `gorm:"type:decimal(10,2);default:0" json:"balance"`
+	Role        string    `gorm:"type:varchar(10);default:guest" json:"role"` // "guest", "staff" or "admin"
+	Locale      string    `gorm:"type:varchar(2);default:id" json:"locale"`   // language of emails, "id" or "en"
 	CreatedAt   time.Time `gorm:"type:timestamp" json:"created_at"`
 }
 
@@ -19,6 +21,7 @@ type UserRegisterPayload struct {
 	Email       string `json:"email" form:"email" query:"email"`
 	Password    string `json:"password" form:"password" query:"password"`
 	PhoneNumber string `json:"phone_number" form:"phone_number" query:"phone_number"`
+	Locale      string `json:"locale" form:"locale" query:"locale"` // "id" (default) or "en"
 }
 
 type UserLoginPayload struct {
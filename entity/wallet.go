@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+type WalletTransfer struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"-"`
+	TransferID    string     `gorm:"type:varchar(50);unique;not null" json:"transfer_id"`
+	SenderID      uint       `gorm:"not null;index" json:"sender_id"`
+	RecipientID   uint       `gorm:"not null;index" json:"recipient_id"`
+	RecipientName string     `gorm:"-" json:"recipient_name"` // shortened, for the sender to check before confirming
+	Amount        float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Note          string     `gorm:"type:varchar(255)" json:"note"`
+	Status        string     `gorm:"type:varchar(10);not null" json:"status"`   // "pending", "completed" or "expired"
+	ExpiresAt     time.Time  `gorm:"type:timestamp;not null" json:"expires_at"` // confirm before this or start again
+	CompletedAt   *time.Time `gorm:"type:timestamp" json:"completed_at"`
+	CreatedAt     time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type WalletLedgerEntry struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	EntryType    string    `gorm:"type:varchar(20);not null" json:"entry_type"` // "top_up", "booking_payment", "wallet_hold", "hold_release", "booking_refund", "transfer_out", "transfer_in", "gift_purchase" or "gift_redeem"
+	Amount       float64   `gorm:"type:decimal(10,2);not null" json:"amount"`   // negative when the balance went down
+	BalanceAfter float64   `gorm:"type:decimal(10,2);not null" json:"balance_after"`
+	Reference    string    `gorm:"type:varchar(60);not null" json:"reference"` // order, payment, transfer or gift code ID
+	Description  string    `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt    time.Time `gorm:"type:timestamp" json:"created_at"`
+}
+
+type GiftCode struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code       string     `gorm:"type:varchar(20);unique;not null" json:"code"`
+	Amount     float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Status     string     `gorm:"type:varchar(10);not null" json:"status"` // "active" or "redeemed"
+	IssuedBy   uint       `gorm:"not null" json:"issued_by"`               // buyer, or the admin who issued it
+	Purchased  bool       `gorm:"not null" json:"purchased"`               // paid from the buyer's wallet rather than issued by an admin
+	Note       string     `gorm:"type:varchar(255)" json:"note"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp;not null" json:"expires_at"`
+	RedeemedBy uint       `gorm:"not null;default:0" json:"redeemed_by,omitempty"`
+	RedeemedAt *time.Time `gorm:"type:timestamp" json:"redeemed_at"`
+	CreatedAt  time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type WalletTransferPayload struct {
+	Recipient string  `json:"recipient"` // email or phone number of the recipient
+	Amount    float64 `json:"amount"`
+	Note      string  `json:"note"`
+}
+
+type ConfirmTransferPayload struct {
+	Password string `json:"password"`
+}
+
+type GiftCodePayload struct {
+	Amount float64 `json:"amount"`
+	Note   string  `json:"note"`
+}
+
+type RedeemGiftCodePayload struct {
+	Code string `json:"code"`
+}
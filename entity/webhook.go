@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+type WebhookSubscription struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	URL       string    `gorm:"type:varchar(255);not null" json:"url"`
+	Secret    string    `gorm:"type:varchar(100);not null" json:"secret,omitempty"` // key of the HMAC signature, only shown when created
+	Events    []string  `gorm:"type:text;serializer:json" json:"events"`
+	Active    bool      `gorm:"not null" json:"active"`
+	CreatedAt time.Time `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp" json:"updated_at"`
+}
+
+// WebhookDelivery is one event to send to one subscription, kept as a log
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID uint       `gorm:"not null;index" json:"subscription_id"`
+	EventID        string     `gorm:"type:varchar(36);not null;index" json:"event_id"` // same for every subscription receiving the event
+	Event          string     `gorm:"type:varchar(30);not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"type:varchar(10);not null;default:pending;index:idx_webhook_deliveries_status_next" json:"status"` // "pending", "delivered" or "failed"
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `gorm:"not null;default:0" json:"response_status"`
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	NextAttemptAt  time.Time  `gorm:"type:timestamp;not null;index:idx_webhook_deliveries_status_next" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"type:timestamp" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"type:timestamp" json:"updated_at"`
+}
+
+type WebhookSubscriptionPayload struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookEvent is the JSON body partners receive
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"lux-hotel/config"
+	"lux-hotel/utils"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +16,10 @@ import (
 func main() {
 	godotenv.Load()
 
+	if err := utils.LoadPaymentMethods(); err != nil {
+		panic("failed to load payment methods: " + err.Error())
+	}
+
 	config.InitDB()
 	DB := config.DB
 
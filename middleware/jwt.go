@@ -13,8 +13,6 @@ import (
 // Custom JWT validation middleware
 func ValidateJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		var JWTSecret = []byte(os.Getenv("JWT_SECRET_KEY"))
-
 		authHeader := c.Request().Header.Get("Authorization")
 
 		if authHeader == "" {
@@ -28,24 +26,74 @@ func ValidateJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid token format"})
 		}
 
-		// Parse and validate the JWT token
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			// Ensure the signing method is HMAC
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-
-			return JWTSecret, nil
-		})
+		claims, err := parseJWT(tokenString)
 
-		if err != nil || !token.Valid {
+		if err != nil {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid or expired token"})
 		}
 
 		// Store the claims in the context for further use
-		claims := token.Claims.(jwt.MapClaims)
 		c.Set("user", claims)
 
 		return next(c)
 	}
 }
+
+// Optional JWT middleware for public endpoints that personalise their response,
+// a missing or invalid token just leaves the request anonymous
+func OptionalJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		authHeader := c.Request().Header.Get("Authorization")
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		if tokenString != authHeader {
+			if claims, err := parseJWT(tokenString); err == nil {
+				c.Set("user", claims)
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// Role check middleware, must be chained after ValidateJWTMiddleware
+func RequireRoleMiddleware(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("user").(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Missing token"})
+			}
+
+			role, _ := claims["role"].(string)
+
+			for _, allowed := range roles {
+				if role == allowed {
+					return next(c)
+				}
+			}
+
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Insufficient permission"})
+		}
+	}
+}
+
+func parseJWT(tokenString string) (jwt.MapClaims, error) {
+	var JWTSecret = []byte(os.Getenv("JWT_SECRET_KEY"))
+
+	// Parse and validate the JWT token
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		// Ensure the signing method is HMAC
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+
+		return JWTSecret, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	return token.Claims.(jwt.MapClaims), nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Guests are reminded of an open balance this many days before it is due
+const balanceReminderLeadDays = 3
+
+type BalanceRepository interface {
+	SendBalanceReminders(limit int) (int, error)
+	CancelOverdueBookings(limit int) (int, error)
+}
+
+type balanceRepository struct {
+	DB      *gorm.DB
+	Payment *paymentRepository
+}
+
+func NewBalanceRepository(db *gorm.DB) BalanceRepository {
+	return &balanceRepository{DB: db, Payment: &paymentRepository{DB: db}}
+}
+
+// SendBalanceReminders emails guests whose balance is due soon, once per booking
+func (br *balanceRepository) SendBalanceReminders(limit int) (int, error) {
+	var bookings []entity.Booking
+
+	remindFrom := time.Now().AddDate(0, 0, balanceReminderLeadDays).Format("2006-01-02")
+
+	result := br.DB.Where("booking_status = ? AND balance_due_date IS NOT NULL AND balance_due_date <= ? AND amount_paid < total_price AND balance_reminded_at IS NULL", "settlement", remindFrom).
+		Order("balance_due_date, id").Limit(limit).Find(&bookings)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	for _, booking := range bookings {
+		err := br.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&booking).UpdateColumn("balance_reminded_at", time.Now()).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			return queueBookingEmail(tx, booking, utils.EmailBalanceReminder)
+		})
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(bookings), nil
+}
+
+// CancelOverdueBookings cancels confirmed bookings whose balance was not paid
+// by the due date. The deposit is kept and the rooms go back on sale.
+func (br *balanceRepository) CancelOverdueBookings(limit int) (int, error) {
+	var bookings []entity.Booking
+
+	result := br.DB.Where("booking_status = ? AND balance_due_date < ? AND amount_paid < total_price", "settlement", time.Now().Format("2006-01-02")).
+		Order("balance_due_date, id").Limit(limit).Find(&bookings)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	cancelled := 0
+
+	for _, booking := range bookings {
+		// A balance payment still open at Midtrans is voided first, one that
+		// cannot be voided may have been paid and leaves the booking alone
+		var pending []entity.Payment
+		if err := br.DB.Where("order_id = ? AND payment_status = ?", booking.OrderID, "pending").Find(&pending).Error; err != nil {
+			return cancelled, fmt.Errorf("500 | %v", err)
+		}
+
+		paying := false
+		for _, payment := range pending {
+			if err := br.Payment.cancelPendingPayment(payment); err != nil {
+				log.Printf("Overdue booking %s kept, payment %s: %v", booking.OrderID, payment.PaymentID, err)
+				paying = true
+			}
+		}
+
+		if paying {
+			continue
+		}
+
+		err := br.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, booking.ID).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			// Paid or cancelled in the meantime
+			if booking.BookingStatus != "settlement" || booking.AmountPaid >= booking.TotalPrice {
+				return nil
+			}
+
+			if err := tx.Model(&booking).Update("booking_status", "cancel").Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ? AND status IN ?", booking.OrderID, []string{"held", "redeemed"}).Update("status", "released").Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			if err := queueBookingEmail(tx, booking, utils.EmailBalanceOverdue); err != nil {
+				return err
+			}
+
+			cancelled++
+
+			return queueWebhookEvent(tx, utils.WebhookBookingCancelled, booking)
+		})
+
+		if err != nil {
+			return cancelled, err
+		}
+	}
+
+	return cancelled, nil
+}
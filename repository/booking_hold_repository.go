@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A pending booking holds its rooms and promo usage this long without a live
+// payment attempt. Attempts at Midtrans are left to their own expiry.
+const bookingHoldTTL = 30 * time.Minute
+
+type BookingHoldRepository interface {
+	ExpireUnpaidBookings(limit int) (int, error)
+}
+
+type bookingHoldRepository struct {
+	DB *gorm.DB
+}
+
+func NewBookingHoldRepository(db *gorm.DB) BookingHoldRepository {
+	return &bookingHoldRepository{DB: db}
+}
+
+// ExpireUnpaidBookings releases pending bookings nobody is paying for, oldest first
+func (br *bookingHoldRepository) ExpireUnpaidBookings(limit int) (int, error) {
+	var bookings []entity.Booking
+
+	cutoff := time.Now().Add(-bookingHoldTTL)
+
+	result := br.DB.Where("booking_status = ? AND created_at < ?", "pending", cutoff).
+		Where("NOT EXISTS (?)", br.livePayments(br.DB, cutoff).Where("payments.order_id = bookings.order_id")).
+		Order("created_at, id").Limit(limit).Find(&bookings)
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	expired := 0
+
+	for _, booking := range bookings {
+		err := br.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, booking.ID).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			// Paid, or a payment was started, in the meantime
+			var live int64
+			if err := br.livePayments(tx, cutoff).Where("payments.order_id = ?", booking.OrderID).Count(&live).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			if booking.BookingStatus != "pending" || live > 0 {
+				return nil
+			}
+
+			if err := tx.Model(&booking).Update("booking_status", "expire").Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			// Give the promo usage back so the code can be used again
+			if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ? AND status = ?", booking.OrderID, "held").Update("status", "released").Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			if err := queueBookingEmail(tx, booking, utils.EmailBookingExpired); err != nil {
+				return err
+			}
+
+			expired++
+
+			booking.BookingStatus = "expire"
+			return queueWebhookEvent(tx, utils.WebhookBookingExpired, booking)
+		})
+
+		if err != nil {
+			return expired, err
+		}
+	}
+
+	return expired, nil
+}
+
+// livePayments selects payment attempts that are still open, or were refused
+// recently enough that the guest may be trying again
+func (br *bookingHoldRepository) livePayments(db *gorm.DB, cutoff time.Time) *gorm.DB {
+	return db.Model(&entity.Payment{}).Select("1").
+		Where("payments.payment_status = ? OR payments.updated_at >= ?", "pending", cutoff)
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository interface {
+	GetRoomCalendar(roomID int, token string) (string, error)
+	GetCalendarExport(roomID int) (*entity.CalendarExportResponse, error)
+	GetCalendarFeeds(roomID int) ([]entity.CalendarFeed, error)
+	CreateCalendarFeed(roomID int, payload entity.CalendarFeedPayload) (*entity.CalendarFeed, error)
+	DeleteCalendarFeed(feedID int) error
+	SyncCalendarFeed(feedID int) (*entity.CalendarFeed, error)
+	SyncCalendarFeeds() error
+	GetAvailabilityBlocks(roomID int) ([]entity.AvailabilityBlock, error)
+}
+
+type calendarRepository struct {
+	DB *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepository{DB: db}
+}
+
+// GetRoomCalendar exports the room's bookings as ICS. Only stays that have not
+// ended are listed and events carry no guest details.
+func (cr *calendarRepository) GetRoomCalendar(roomID int, token string) (string, error) {
+	room, err := cr.getRoomByID(roomID)
+	if err != nil {
+		return "", err
+	}
+
+	if room.CalendarToken == "" || room.CalendarToken != token {
+		return "", fmt.Errorf("401 | Unauthorized access")
+	}
+
+	var bookings []entity.Booking
+
+	// Bookings made before multi-room orders only carry room_id
+	result := cr.DB.Where("booking_status IN ? AND check_out >= ?", roomHoldingStatuses, time.Now().Format("2006-01-02")).
+		Where(cr.DB.Where("id IN (?)", cr.DB.Model(&entity.BookingRoom{}).Select("booking_id").Where("room_id = ? AND status = ?", room.ID, "active")).
+			Or("room_id = ? AND NOT EXISTS (SELECT 1 FROM booking_rooms WHERE booking_rooms.booking_id = bookings.id)", room.ID)).
+		Order("check_in").Find(&bookings)
+
+	if result.Error != nil {
+		return "", fmt.Errorf("500 | %v", result.Error)
+	}
+
+	events := make([]utils.ICSEvent, 0, len(bookings))
+	stamp := time.Unix(0, 0)
+
+	for _, booking := range bookings {
+		checkIn, _ := time.Parse("2006-01-02", booking.CheckIn[:10])
+		checkOut, _ := time.Parse("2006-01-02", booking.CheckOut[:10])
+
+		events = append(events, utils.ICSEvent{
+			UID:     booking.OrderID + "@lux-hotel",
+			Summary: "Booked",
+			Start:   checkIn,
+			End:     checkOut,
+		})
+
+		if booking.UpdatedAt.After(stamp) {
+			stamp = booking.UpdatedAt
+		}
+	}
+
+	return utils.BuildICS("Room "+room.RoomNumber, events, stamp), nil
+}
+
+// GetCalendarExport returns the feed URL of a room, creating its token on first use
+func (cr *calendarRepository) GetCalendarExport(roomID int) (*entity.CalendarExportResponse, error) {
+	room, err := cr.getRoomByID(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if room.CalendarToken == "" {
+		room.CalendarToken = uuid.New().String()
+
+		if err := cr.DB.Model(room).Update("calendar_token", room.CalendarToken).Error; err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	return &entity.CalendarExportResponse{
+		RoomID: room.ID,
+		URL:    fmt.Sprintf("/api/rooms/%d/calendar.ics?token=%s", room.ID, room.CalendarToken),
+	}, nil
+}
+
+func (cr *calendarRepository) GetCalendarFeeds(roomID int) ([]entity.CalendarFeed, error) {
+	var feeds []entity.CalendarFeed
+
+	if _, err := cr.getRoomByID(roomID); err != nil {
+		return nil, err
+	}
+
+	if result := cr.DB.Where("room_id = ?", roomID).Order("id").Find(&feeds); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return feeds, nil
+}
+
+func (cr *calendarRepository) CreateCalendarFeed(roomID int, payload entity.CalendarFeedPayload) (*entity.CalendarFeed, error) {
+	room, err := cr.getRoomByID(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := entity.CalendarFeed{
+		RoomID: room.ID,
+		Name:   payload.Name,
+		Source: payload.Source,
+		Active: payload.Active == nil || *payload.Active,
+	}
+
+	if result := cr.DB.Create(&feed); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	// Import right away so the blocks show up without waiting for the worker
+	if feed.Active {
+		cr.syncFeed(&feed)
+	}
+
+	return &feed, nil
+}
+
+func (cr *calendarRepository) DeleteCalendarFeed(feedID int) error {
+	feed, err := cr.getFeedByID(feedID)
+	if err != nil {
+		return err
+	}
+
+	return cr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("feed_id = ?", feed.ID).Delete(&entity.AvailabilityBlock{}).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if err := tx.Delete(feed).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+}
+
+func (cr *calendarRepository) SyncCalendarFeed(feedID int) (*entity.CalendarFeed, error) {
+	feed, err := cr.getFeedByID(feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cr.syncFeed(feed); err != nil {
+		return nil, fmt.Errorf("400 | Calendar import failed: %v", err)
+	}
+
+	return feed, nil
+}
+
+// SyncCalendarFeeds imports every active feed, a failing feed does not stop the others
+func (cr *calendarRepository) SyncCalendarFeeds() error {
+	var feeds []entity.CalendarFeed
+
+	if result := cr.DB.Where("active = ?", true).Find(&feeds); result.Error != nil {
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	for i := range feeds {
+		if err := cr.syncFeed(&feeds[i]); err != nil {
+			log.Printf("Calendar feed %d import failed: %v", feeds[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (cr *calendarRepository) GetAvailabilityBlocks(roomID int) ([]entity.AvailabilityBlock, error) {
+	var blocks []entity.AvailabilityBlock
+
+	if _, err := cr.getRoomByID(roomID); err != nil {
+		return nil, err
+	}
+
+	if result := cr.DB.Where("room_id = ? AND end_date >= ?", roomID, time.Now().Format("2006-01-02")).Order("start_date").Find(&blocks); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return blocks, nil
+}
+
+// syncFeed replaces the feed's blocks with the events currently in its calendar
+// and records the outcome on the feed
+func (cr *calendarRepository) syncFeed(feed *entity.CalendarFeed) error {
+	err := cr.importFeed(feed)
+
+	now := time.Now()
+	feed.LastSyncedAt = &now
+	feed.LastError = ""
+	if err != nil {
+		feed.LastError = err.Error()
+	}
+
+	cr.DB.Model(feed).Updates(map[string]interface{}{
+		"last_synced_at": feed.LastSyncedAt,
+		"last_error":     feed.LastError,
+	})
+
+	return err
+}
+
+func (cr *calendarRepository) importFeed(feed *entity.CalendarFeed) error {
+	source, err := utils.OpenICSSource(feed.Source)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	events, err := utils.ParseICS(source)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	return cr.DB.Transaction(func(tx *gorm.DB) error {
+		uids := []string{}
+
+		for _, event := range events {
+			// Past stays cannot clash with new bookings
+			if event.End.Format("2006-01-02") < today {
+				continue
+			}
+
+			block := entity.AvailabilityBlock{
+				RoomID:    feed.RoomID,
+				FeedID:    feed.ID,
+				UID:       event.UID,
+				StartDate: event.Start.Format("2006-01-02"),
+				EndDate:   event.End.Format("2006-01-02"),
+				Summary:   event.Summary,
+			}
+
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "feed_id"}, {Name: "uid"}},
+				DoUpdates: clause.AssignmentColumns([]string{"start_date", "end_date", "summary", "updated_at"}),
+			}).Create(&block).Error
+			if err != nil {
+				return err
+			}
+
+			uids = append(uids, event.UID)
+		}
+
+		// Events removed from the calendar free the room again
+		query := tx.Where("feed_id = ?", feed.ID)
+		if len(uids) > 0 {
+			query = query.Where("uid NOT IN ?", uids)
+		}
+
+		return query.Delete(&entity.AvailabilityBlock{}).Error
+	})
+}
+
+func (cr *calendarRepository) getRoomByID(roomID int) (*entity.Room, error) {
+	var room entity.Room
+
+	result := cr.DB.First(&room, roomID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Room not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &room, nil
+}
+
+func (cr *calendarRepository) getFeedByID(feedID int) (*entity.CalendarFeed, error) {
+	var feed entity.CalendarFeed
+
+	result := cr.DB.First(&feed, feedID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Calendar feed not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &feed, nil
+}
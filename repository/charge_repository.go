@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+
+	"gorm.io/gorm"
+)
+
+type ChargeRepository interface {
+	GetHotelCharges(hotelID int) ([]entity.HotelCharge, error)
+	CreateHotelCharge(hotelID int, payload entity.HotelChargePayload) (*entity.HotelCharge, error)
+	UpdateHotelCharge(chargeID int, payload entity.HotelChargePayload) (*entity.HotelCharge, error)
+	DeleteHotelCharge(chargeID int) error
+}
+
+type chargeRepository struct {
+	DB *gorm.DB
+}
+
+func NewChargeRepository(db *gorm.DB) ChargeRepository {
+	return &chargeRepository{DB: db}
+}
+
+func (cr *chargeRepository) GetHotelCharges(hotelID int) ([]entity.HotelCharge, error) {
+	var charges []entity.HotelCharge
+
+	if err := cr.ensureHotelExists(hotelID); err != nil {
+		return nil, err
+	}
+
+	result := cr.DB.Where("hotel_id = ?", hotelID).Order("sort_order, id").Find(&charges)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return charges, nil
+}
+
+func (cr *chargeRepository) CreateHotelCharge(hotelID int, payload entity.HotelChargePayload) (*entity.HotelCharge, error) {
+	if err := cr.ensureHotelExists(hotelID); err != nil {
+		return nil, err
+	}
+
+	charge := entity.HotelCharge{HotelID: uint(hotelID), Active: true}
+	cr.applyChargePayload(&charge, payload)
+
+	if result := cr.DB.Create(&charge); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &charge, nil
+}
+
+func (cr *chargeRepository) UpdateHotelCharge(chargeID int, payload entity.HotelChargePayload) (*entity.HotelCharge, error) {
+	charge, err := cr.getChargeByID(chargeID)
+	if err != nil {
+		return nil, err
+	}
+
+	cr.applyChargePayload(charge, payload)
+
+	// Save writes zero values too, so flags can be switched off
+	if result := cr.DB.Save(charge); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return charge, nil
+}
+
+func (cr *chargeRepository) DeleteHotelCharge(chargeID int) error {
+	charge, err := cr.getChargeByID(chargeID)
+	if err != nil {
+		return err
+	}
+
+	if result := cr.DB.Delete(charge); result.Error != nil {
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return nil
+}
+
+func (cr *chargeRepository) getChargeByID(chargeID int) (*entity.HotelCharge, error) {
+	var charge entity.HotelCharge
+
+	result := cr.DB.First(&charge, chargeID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Charge not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &charge, nil
+}
+
+func (cr *chargeRepository) ensureHotelExists(hotelID int) error {
+	var hotel entity.Hotel
+
+	result := cr.DB.First(&hotel, hotelID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return fmt.Errorf("404 | Hotel not found")
+		}
+
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return nil
+}
+
+func (cr *chargeRepository) applyChargePayload(charge *entity.HotelCharge, payload entity.HotelChargePayload) {
+	charge.Name = payload.Name
+	charge.ChargeType = payload.ChargeType
+	charge.Amount = payload.Amount
+	charge.Basis = payload.Basis
+	charge.Inclusive = payload.Inclusive
+	charge.Compound = payload.Compound
+	charge.SortOrder = payload.SortOrder
+
+	if charge.Basis == "" {
+		charge.Basis = "per_stay"
+	}
+
+	if payload.Active != nil {
+		charge.Active = *payload.Active
+	}
+}
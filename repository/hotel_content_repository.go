@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+
+	"gorm.io/gorm"
+)
+
+type HotelContentRepository interface {
+	UpdateHotelContent(hotelID int, payload entity.HotelContentPayload) (*entity.Hotel, error)
+	UpdateDepositPolicy(hotelID int, payload entity.DepositPolicyPayload) (*entity.Hotel, error)
+	AddHotelPhoto(hotelID int, image io.Reader, payload entity.HotelPhotoPayload) (*entity.HotelPhoto, error)
+	UpdateHotelPhoto(photoID int, payload entity.HotelPhotoPayload) (*entity.HotelPhoto, error)
+	ReorderHotelPhotos(hotelID int, payload entity.HotelPhotoOrderPayload) ([]entity.HotelPhoto, error)
+	DeleteHotelPhoto(photoID int) error
+}
+
+type hotelContentRepository struct {
+	DB *gorm.DB
+}
+
+func NewHotelContentRepository(db *gorm.DB) HotelContentRepository {
+	return &hotelContentRepository{DB: db}
+}
+
+func (hr *hotelContentRepository) UpdateHotelContent(hotelID int, payload entity.HotelContentPayload) (*entity.Hotel, error) {
+	hotel, err := hr.getHotelByID(hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	hotel.Name = payload.Name
+	hotel.Location = payload.Location
+	hotel.ContactNumber = payload.ContactNumber
+	hotel.Email = payload.Email
+	hotel.Description = payload.Description
+	hotel.StarRating = payload.StarRating
+	hotel.CheckInTime = payload.CheckInTime
+	hotel.CheckOutTime = payload.CheckOutTime
+	hotel.Amenities = payload.Amenities
+	hotel.Latitude = payload.Latitude
+	hotel.Longitude = payload.Longitude
+
+	if result := hr.DB.Omit("Photos", "Rooms", "RoomTypes").Save(hotel); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return hotel, nil
+}
+
+// UpdateDepositPolicy changes how new bookings may be paid. Existing bookings
+// keep the terms they were made with.
+func (hr *hotelContentRepository) UpdateDepositPolicy(hotelID int, payload entity.DepositPolicyPayload) (*entity.Hotel, error) {
+	hotel, err := hr.getHotelByID(hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	hotel.DepositPercent = payload.DepositPercent
+	hotel.DepositMinNights = payload.DepositMinNights
+	hotel.BalanceDueDays = payload.BalanceDueDays
+	hotel.BalanceAtDesk = payload.BalanceAtDesk
+
+	if result := hr.DB.Omit("Photos", "Rooms", "RoomTypes").Save(hotel); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return hotel, nil
+}
+
+func (hr *hotelContentRepository) AddHotelPhoto(hotelID int, image io.Reader, payload entity.HotelPhotoPayload) (*entity.HotelPhoto, error) {
+	hotel, err := hr.getHotelByID(hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := utils.StoreImage(fmt.Sprintf("hotels/%d", hotel.ID), image)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidImage) {
+			return nil, fmt.Errorf("400 | %v", err)
+		}
+
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	photo := entity.HotelPhoto{
+		HotelID:      hotel.ID,
+		URL:          stored.URL,
+		ThumbnailURL: stored.ThumbnailURL,
+		Caption:      payload.Caption,
+		SortOrder:    payload.SortOrder,
+	}
+
+	if result := hr.DB.Create(&photo); result.Error != nil {
+		utils.RemoveImage(stored.URL)
+		utils.RemoveImage(stored.ThumbnailURL)
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &photo, nil
+}
+
+func (hr *hotelContentRepository) UpdateHotelPhoto(photoID int, payload entity.HotelPhotoPayload) (*entity.HotelPhoto, error) {
+	photo, err := hr.getPhotoByID(photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo.Caption = payload.Caption
+	photo.SortOrder = payload.SortOrder
+
+	if result := hr.DB.Save(photo); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return photo, nil
+}
+
+// ReorderHotelPhotos sets the sort order of a hotel's photos to their position in the list
+func (hr *hotelContentRepository) ReorderHotelPhotos(hotelID int, payload entity.HotelPhotoOrderPayload) ([]entity.HotelPhoto, error) {
+	var photos []entity.HotelPhoto
+
+	if _, err := hr.getHotelByID(hotelID); err != nil {
+		return nil, err
+	}
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		for position, photoID := range payload.PhotoIDs {
+			result := tx.Model(&entity.HotelPhoto{}).Where("id = ? AND hotel_id = ?", photoID, hotelID).Update("sort_order", position)
+
+			if result.Error != nil {
+				return fmt.Errorf("500 | %v", result.Error)
+			}
+
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("404 | Photo %d not found in this hotel", photoID)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result := hr.DB.Where("hotel_id = ?", hotelID).Order("sort_order, id").Find(&photos); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return photos, nil
+}
+
+func (hr *hotelContentRepository) DeleteHotelPhoto(photoID int) error {
+	photo, err := hr.getPhotoByID(photoID)
+	if err != nil {
+		return err
+	}
+
+	if result := hr.DB.Delete(photo); result.Error != nil {
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	utils.RemoveImage(photo.URL)
+	utils.RemoveImage(photo.ThumbnailURL)
+
+	return nil
+}
+
+func (hr *hotelContentRepository) getHotelByID(hotelID int) (*entity.Hotel, error) {
+	var hotel entity.Hotel
+
+	result := hr.DB.First(&hotel, hotelID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Hotel not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &hotel, nil
+}
+
+func (hr *hotelContentRepository) getPhotoByID(photoID int) (*entity.HotelPhoto, error) {
+	var photo entity.HotelPhoto
+
+	result := hr.DB.First(&photo, photoID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Photo not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &photo, nil
+}
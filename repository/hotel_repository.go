@@ -4,18 +4,38 @@ import (
 	"fmt"
 	"lux-hotel/entity"
 	"lux-hotel/utils"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type HotelRepository interface {
-	GetHotelList() ([]entity.GetHotelList, error)
-	GetHotelDetail(id int) (entity.Hotel, error)
+	GetHotelList(filter entity.HotelListFilter) ([]entity.GetHotelList, error)
+	GetHotelDetail(id int, userID uint) (entity.Hotel, error)
+	SearchHotels(query string, limit int) ([]entity.HotelSearchResult, error)
 	Booking(userID, hotelID int, request entity.BookingRequest) (*entity.Booking, error)
+	Quote(hotelID int, request entity.BookingRequest) (*entity.BookingQuote, error)
+	CancelBookingRoom(userID int, orderID string, lineID int) (*entity.Booking, float64, error)
+	AssignBookingRoom(orderID string, payload entity.AssignBookingRoomPayload) (*entity.BookingRoom, error)
+	GetBookingDetail(userID int, orderID string) (*entity.BookingDetailResponse, error)
+	CheckIn(staffID int, bookingCode string, payload entity.CheckInPayload) (*entity.Booking, error)
+	CheckOut(bookingCode string, payload entity.CheckOutPayload) (*entity.Booking, error)
+	MarkNoShow(bookingCode string) (*entity.Booking, error)
 }
 
+// Booking codes drawn before giving up on a new booking
+const maxBookingCodeAttempts = 5
+
+// Booking statuses that hold their rooms for the stay dates
+var roomHoldingStatuses = []string{"pending", "settlement", "checked_in"}
+
+// Columns of entity.GetHotelList, aggregated over the hotel's available rooms
+const hotelListColumns = "hotels.id, hotels.name, hotels.location, hotels.star_rating, hotels.average_rating, hotels.review_count, hotels.latitude, hotels.longitude, MIN(rooms.price) AS price, COUNT(rooms.id) AS available_rooms"
+
 type hotelRepository struct {
 	DB *gorm.DB
 }
@@ -24,28 +44,126 @@ func NewHotelRepository(db *gorm.DB) HotelRepository {
 	return &hotelRepository{DB: db}
 }
 
-func (hr *hotelRepository) GetHotelList() ([]entity.GetHotelList, error) {
+func (hr *hotelRepository) GetHotelList(filter entity.HotelListFilter) ([]entity.GetHotelList, error) {
 	var hotels []entity.GetHotelList
 
 	// Perform a join between Hotel and Room tables to get only available rooms
-	result := hr.DB.Table("hotels").
-		Select("hotels.id, hotels.name, hotels.location, MIN(rooms.price) AS price, COUNT(rooms.id) AS available_rooms").
+	query := hr.DB.Table("hotels").
+		Select(hotelListColumns).
 		Joins("JOIN rooms ON rooms.hotel_id = hotels.id").
 		Where("rooms.status = ?", "Available").
-		Group("hotels.id").
-		Scan(&hotels)
+		Group("hotels.id")
+
+	if filter.MinPrice > 0 {
+		query = query.Where("rooms.price >= ?", filter.MinPrice)
+	}
+
+	if filter.MaxPrice > 0 {
+		query = query.Where("rooms.price <= ?", filter.MaxPrice)
+	}
+
+	if filter.Latitude != nil && filter.Longitude != nil {
+		query = hr.applyDistanceFilter(query, *filter.Latitude, *filter.Longitude, filter.RadiusKm)
+	}
+
+	sort := filter.Sort
+	if sort == "" && filter.Latitude != nil && filter.Longitude != nil {
+		sort = "distance"
+	}
+
+	switch sort {
+	case "distance":
+		query = query.Order("distance")
+	case "price_asc":
+		query = query.Order("price")
+	case "price_desc":
+		query = query.Order("price DESC")
+	case "rating":
+		query = query.Order("hotels.average_rating DESC, hotels.review_count DESC")
+	}
+
+	result := query.Scan(&hotels)
 
 	if result.Error != nil {
 		return nil, fmt.Errorf("500 | %v", result.Error)
 	}
 
+	favorites, err := hr.getFavoriteHotelIDs(filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range hotels {
+		hotels[i].IsFavorite = favorites[hotels[i].ID]
+	}
+
 	return hotels, nil
 }
 
-func (hr *hotelRepository) GetHotelDetail(id int) (entity.Hotel, error) {
+func (hr *hotelRepository) getFavoriteHotelIDs(userID uint) (map[uint]bool, error) {
+	favorites := map[uint]bool{}
+
+	if userID == 0 {
+		return favorites, nil
+	}
+
+	var hotelIDs []uint
+	if err := hr.DB.Model(&entity.Favorite{}).Where("user_id = ?", userID).Pluck("hotel_id", &hotelIDs).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	for _, hotelID := range hotelIDs {
+		favorites[hotelID] = true
+	}
+
+	return favorites, nil
+}
+
+// applyDistanceFilter adds the distance from the point as a "distance" column and,
+// when a radius is given, keeps only hotels within it. Hotels without coordinates are skipped.
+func (hr *hotelRepository) applyDistanceFilter(query *gorm.DB, latitude, longitude, radiusKm float64) *gorm.DB {
+	query = query.Where("hotels.latitude IS NOT NULL AND hotels.longitude IS NOT NULL")
+
+	if utils.UsePostGIS() {
+		point := "ST_SetSRID(ST_MakePoint(hotels.longitude, hotels.latitude), 4326)::geography"
+		origin := "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+
+		query = query.Select(hotelListColumns+", ST_Distance("+point+", "+origin+") / 1000 AS distance", longitude, latitude)
+
+		if radiusKm > 0 {
+			query = query.Where("ST_DWithin("+point+", "+origin+", ?)", longitude, latitude, radiusKm*1000)
+		}
+
+		return query
+	}
+
+	haversine := fmt.Sprintf("%f * ACOS(LEAST(1, COS(RADIANS(?)) * COS(RADIANS(hotels.latitude)) * COS(RADIANS(hotels.longitude) - RADIANS(?)) + SIN(RADIANS(?)) * SIN(RADIANS(hotels.latitude))))", utils.EarthRadiusKm)
+
+	query = query.Select(hotelListColumns+", "+haversine+" AS distance", latitude, longitude, latitude)
+
+	if radiusKm > 0 {
+		// The bounding box lets the planner discard far away hotels before the trigonometry
+		minLat, maxLat, minLng, maxLng := utils.BoundingBox(latitude, longitude, radiusKm)
+
+		query = query.Where("hotels.latitude BETWEEN ? AND ?", minLat, maxLat)
+
+		// Boxes crossing the antimeridian are only limited by latitude
+		if minLng >= -180 && maxLng <= 180 {
+			query = query.Where("hotels.longitude BETWEEN ? AND ?", minLng, maxLng)
+		}
+
+		query = query.Where(haversine+" <= ?", latitude, longitude, latitude, radiusKm)
+	}
+
+	return query
+}
+
+func (hr *hotelRepository) GetHotelDetail(id int, userID uint) (entity.Hotel, error) {
 	var hotel entity.Hotel
 
-	result := hr.DB.Preload("Rooms").First(&hotel, id)
+	result := hr.DB.Preload("Photos", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order, id")
+	}).Preload("Rooms").Preload("RoomTypes").First(&hotel, id)
 
 	if result.Error != nil {
 		if result.Error.Error() == "record not found" {
@@ -55,19 +173,118 @@ func (hr *hotelRepository) GetHotelDetail(id int) (entity.Hotel, error) {
 		return hotel, fmt.Errorf("500 | %v", result.Error)
 	}
 
+	if userID != 0 {
+		favorite := hr.DB.Where("user_id = ? AND hotel_id = ?", userID, hotel.ID).First(&entity.Favorite{})
+		hotel.IsFavorite = favorite.RowsAffected > 0
+	}
+
 	return hotel, nil
 }
 
+// SearchHotels ranks hotels by full-text match on name, location, amenities and
+// description, using prefix matching for partial words and trigram similarity on
+// name and location so misspelt queries still find something.
+func (hr *hotelRepository) SearchHotels(query string, limit int) ([]entity.HotelSearchResult, error) {
+	var results []entity.HotelSearchResult
+
+	tsQuery := utils.PrefixTSQuery(query)
+	if tsQuery == "" {
+		return nil, fmt.Errorf("400 | Search query has no searchable words")
+	}
+
+	result := hr.DB.Raw(`
+		WITH search AS (SELECT to_tsquery('simple', ?) AS query, ?::text AS term)
+		SELECT hotels.id, hotels.name, hotels.location, hotels.star_rating,
+			ts_rank(hotels.search_vector, search.query) + GREATEST(word_similarity(search.term, hotels.name), word_similarity(search.term, hotels.location)) AS rank,
+			ts_headline('simple', hotels.name || ' - ' || hotels.location || ' ' || coalesce(hotels.description, ''), search.query,
+				'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=8, MaxFragments=2') AS snippet
+		FROM hotels, search
+		WHERE hotels.search_vector @@ search.query
+			OR word_similarity(search.term, hotels.name) >= ?
+			OR word_similarity(search.term, hotels.location) >= ?
+		ORDER BY rank DESC, hotels.id
+		LIMIT ?`,
+		tsQuery, query, utils.SearchSimilarityThreshold, utils.SearchSimilarityThreshold, limit,
+	).Scan(&results)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return results, nil
+}
+
 func (hr *hotelRepository) Booking(userID, hotelID int, request entity.BookingRequest) (*entity.Booking, error) {
+	var booking entity.Booking
+
+	// Promo usage is counted and held in the same transaction as the booking
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		quote, promo, err := hr.calculateQuote(tx, uint(userID), hotelID, request)
+		if err != nil {
+			return err
+		}
+
+		// Get user
+		user, err := hr.getUserByID(uint(userID))
+
+		if err != nil {
+			return err
+		}
+
+		orderID := fmt.Sprintf("BKNG-%d%s", userID, uuid.New().String())
+
+		booking = hr.createBookingEntity(orderID, *user, *quote)
+
+		if err := hr.insertBooking(tx, &booking); err != nil {
+			return err
+		}
+
+		if promo != nil {
+			redemption := entity.PromoRedemption{
+				PromoCodeID:    promo.ID,
+				UserID:         user.UserID,
+				BookingID:      booking.ID,
+				OrderID:        booking.OrderID,
+				DiscountAmount: booking.Discount,
+				Status:         "held",
+			}
+
+			if insertRedemption := tx.Create(&redemption); insertRedemption.Error != nil {
+				return fmt.Errorf("500 | %v", insertRedemption.Error)
+			}
+		}
+
+		if err := queueBookingEmail(tx, booking, utils.EmailBookingCreated); err != nil {
+			return err
+		}
+
+		return queueWebhookEvent(tx, utils.WebhookBookingCreated, booking)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+func (hr *hotelRepository) Quote(hotelID int, request entity.BookingRequest) (*entity.BookingQuote, error) {
+	quote, _, err := hr.calculateQuote(hr.DB, 0, hotelID, request)
+
+	return quote, err
+}
+
+// calculateQuote prices a stay for the given user, who may be 0 for anonymous quotes
+func (hr *hotelRepository) calculateQuote(db *gorm.DB, userID uint, hotelID int, request entity.BookingRequest) (*entity.BookingQuote, *entity.PromoCode, error) {
 	// Parse checkin and checkout
 	checkIn, checkOut, parseDateError := hr.parseBookingDates(request.CheckIn, request.CheckOut)
 
 	if parseDateError != nil {
-		return nil, parseDateError
+		return nil, nil, parseDateError
 	}
 
 	if validateDateErr := hr.validateDate(checkIn, checkOut); validateDateErr != nil {
-		return nil, validateDateErr
+		return nil, nil, validateDateErr
 	}
 
 	// Total days is the difference in time divided by 24 hours
@@ -75,39 +292,1115 @@ func (hr *hotelRepository) Booking(userID, hotelID int, request entity.BookingRe
 
 	// Get hotel
 	hotel, err := hr.getHotelByID(hotelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get rooms and check each against its capacity
+	rooms, subTotal, err := hr.priceRequestedRooms(db, uint(hotelID), hr.requestedRooms(request), checkIn, checkOut, totalDays)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get tax and fee rules
+	rules, err := hr.getHotelCharges(hotel.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var promo *entity.PromoCode
+	discount := 0.0
+
+	if code := strings.ToUpper(strings.TrimSpace(request.PromoCode)); code != "" {
+		promo, discount, err = hr.resolvePromoCode(db, code, userID, hotel.ID, totalDays, subTotal)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Taxes and fees are charged on the discounted room price
+	charges, chargesTotal := utils.CalculateCharges(subTotal-discount, totalDays*len(rooms), rules)
+
+	quote := &entity.BookingQuote{
+		HotelID:    hotel.ID,
+		Rooms:      rooms,
+		CheckIn:    checkIn.Format("2006-01-02"),
+		CheckOut:   checkOut.Format("2006-01-02"),
+		TotalDays:  totalDays,
+		SubTotal:   subTotal,
+		Discount:   discount,
+		Charges:    charges,
+		TotalPrice: utils.RoundPrice(subTotal - discount + chargesTotal),
+	}
+
+	if promo != nil {
+		quote.PromoCode = promo.Code
+	}
+
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+
+	var dueDate *time.Time
+	quote.DepositPercent, dueDate = utils.DepositTerms(*hotel, checkIn, totalDays, today)
+
+	if quote.DepositPercent > 0 {
+		quote.DepositAmount = utils.DepositAmount(quote.TotalPrice, quote.DepositPercent)
+	}
+
+	if dueDate != nil {
+		quote.BalanceDueDate = dueDate.Format("2006-01-02")
+	}
+
+	return quote, promo, nil
+}
+
+// requestedRooms accepts both the multi-room list and the single room_id form
+func (hr *hotelRepository) requestedRooms(request entity.BookingRequest) []entity.BookingRoomRequest {
+	if len(request.Rooms) > 0 {
+		return request.Rooms
+	}
+
+	// Older clients do not send guest counts
+	adults := request.Adults
+	if adults == 0 {
+		adults = 1
+	}
+
+	return []entity.BookingRoomRequest{
+		{RoomID: request.RoomID, RoomTypeID: request.RoomTypeID, Adults: adults, Children: request.Children},
+	}
+}
+
+func (hr *hotelRepository) priceRequestedRooms(db *gorm.DB, hotelID uint, requested []entity.BookingRoomRequest, checkIn, checkOut time.Time, totalDays int) ([]entity.BookingRoom, float64, error) {
+	if err := hr.lockRequestedRooms(db, hotelID, requested); err != nil {
+		return nil, 0, err
+	}
+
+	rooms := make([]entity.BookingRoom, 0, len(requested))
+	subTotal := 0.0
+	typeCounts := map[uint]int{}
+
+	for _, request := range requested {
+		var line entity.BookingRoom
+		var baseRate float64
+		var maxAdults, maxChildren int
+		var label string
+
+		if request.RoomID != 0 {
+			room, err := hr.getHotelRoom(db, hotelID, request.RoomID)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if err := hr.checkRoomBookings(db, room.ID, 0, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02")); err != nil {
+				return nil, 0, err
+			}
+
+			if err := hr.checkAvailabilityBlocks(db, room.ID, checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02")); err != nil {
+				return nil, 0, err
+			}
+
+			// Unassigned lines of the type may be counting on this very room
+			if room.RoomTypeID != nil {
+				typeCounts[*room.RoomTypeID]++
+
+				if _, err := hr.getAvailableRoomType(db, hotelID, *room.RoomTypeID, checkIn, checkOut, typeCounts[*room.RoomTypeID]); err != nil {
+					return nil, 0, err
+				}
+			}
+
+			line = entity.BookingRoom{RoomID: room.ID, RoomTypeID: room.RoomTypeID, RoomNumber: room.RoomNumber}
+			baseRate, label = room.Price, "Room "+room.RoomNumber
+			maxAdults, maxChildren = room.MaxAdults, room.MaxChildren
+
+			if room.Type != nil {
+				line.RoomTypeName = room.Type.Name
+				maxAdults, maxChildren = room.Type.MaxAdults, room.Type.MaxChildren
+			}
+		} else {
+			// Room type bookings get a specific room assigned later
+			typeCounts[request.RoomTypeID]++
+
+			roomType, err := hr.getAvailableRoomType(db, hotelID, request.RoomTypeID, checkIn, checkOut, typeCounts[request.RoomTypeID])
+			if err != nil {
+				return nil, 0, err
+			}
+
+			line = entity.BookingRoom{RoomTypeID: &roomType.ID, RoomTypeName: roomType.Name}
+			baseRate, label = roomType.BaseRate, roomType.Name
+			maxAdults, maxChildren = roomType.MaxAdults, roomType.MaxChildren
+		}
+
+		if request.Adults > maxAdults || request.Children > maxChildren {
+			return nil, 0, fmt.Errorf("400 | %s fits at most %d adult(s) and %d child(ren)", label, maxAdults, maxChildren)
+		}
+
+		line.Adults = request.Adults
+		line.Children = request.Children
+		line.RoomRate = utils.RoundPrice(utils.AdjustPrice(baseRate, checkIn))
+		line.SubTotal = utils.RoundPrice(float64(totalDays) * line.RoomRate)
+		line.Status = "active"
+		subTotal += line.SubTotal
+
+		rooms = append(rooms, line)
+	}
+
+	return rooms, utils.RoundPrice(subTotal), nil
+}
+
+// lockRequestedRooms locks the requested rooms, then their room types, in ID
+// order. Concurrent bookings of the same rooms are checked one after the other
+// instead of both passing before either is saved.
+func (hr *hotelRepository) lockRequestedRooms(db *gorm.DB, hotelID uint, requested []entity.BookingRoomRequest) error {
+	var roomIDs, typeIDs []uint
+
+	for _, request := range requested {
+		if request.RoomID != 0 {
+			roomIDs = append(roomIDs, request.RoomID)
+		} else {
+			typeIDs = append(typeIDs, request.RoomTypeID)
+		}
+	}
+
+	if len(roomIDs) > 0 {
+		var rooms []entity.Room
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hotel_id = ? AND id IN ?", hotelID, roomIDs).Order("id").Find(&rooms).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		for _, room := range rooms {
+			if room.RoomTypeID != nil {
+				typeIDs = append(typeIDs, *room.RoomTypeID)
+			}
+		}
+	}
+
+	if len(typeIDs) > 0 {
+		var roomTypes []entity.RoomType
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hotel_id = ? AND id IN ?", hotelID, typeIDs).Order("id").Find(&roomTypes).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	return nil
+}
+
+// getAvailableRoomType checks that the type still has a free room for the stay once
+// other bookings of the type, assigned or not, and the rooms already requested are counted
+func (hr *hotelRepository) getAvailableRoomType(db *gorm.DB, hotelID, roomTypeID uint, checkIn, checkOut time.Time, requested int) (*entity.RoomType, error) {
+	var roomType entity.RoomType
+
+	result := db.Where("hotel_id = ? AND id = ?", hotelID, roomTypeID).First(&roomType)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Room type not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	var rooms int64
+	if err := db.Model(&entity.Room{}).Where("room_type_id = ?", roomTypeID).Count(&rooms).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	// Lines of the type hold a room whether or not one is assigned yet
+	var held int64
+	err := db.Table("booking_rooms").
+		Joins("JOIN bookings ON bookings.id = booking_rooms.booking_id").
+		Where("booking_rooms.room_type_id = ? AND booking_rooms.status = ?", roomTypeID, "active").
+		Where("bookings.booking_status IN ?", roomHoldingStatuses).
+		Where("bookings.check_in < ? AND bookings.check_out > ?", checkOut.Format("2006-01-02"), checkIn.Format("2006-01-02")).
+		Count(&held).Error
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	// Rooms closed by an imported calendar cannot take these guests either
+	var blocked int64
+	err = db.Model(&entity.AvailabilityBlock{}).
+		Joins("JOIN rooms ON rooms.id = availability_blocks.room_id").
+		Where("rooms.room_type_id = ?", roomTypeID).
+		Where("availability_blocks.start_date < ? AND availability_blocks.end_date > ?", checkOut.Format("2006-01-02"), checkIn.Format("2006-01-02")).
+		Distinct("availability_blocks.room_id").Count(&blocked).Error
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if rooms-held-blocked < int64(requested) {
+		return nil, fmt.Errorf("400 | No %s rooms left for these dates", roomType.Name)
+	}
+
+	return &roomType, nil
+}
+
+// checkRoomBookings rejects stays overlapping another booking of the room.
+// excludeLineID skips the booking line being changed.
+func (hr *hotelRepository) checkRoomBookings(db *gorm.DB, roomID, excludeLineID uint, checkIn, checkOut string) error {
+	var clashes int64
+
+	err := db.Table("booking_rooms").
+		Joins("JOIN bookings ON bookings.id = booking_rooms.booking_id").
+		Where("booking_rooms.room_id = ? AND booking_rooms.status = ? AND booking_rooms.id <> ?", roomID, "active", excludeLineID).
+		Where("bookings.booking_status IN ?", roomHoldingStatuses).
+		Where("bookings.check_in < ? AND bookings.check_out > ?", checkOut, checkIn).
+		Count(&clashes).Error
+	if err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	if clashes > 0 {
+		return fmt.Errorf("409 | Room is already booked for these dates")
+	}
+
+	return nil
+}
+
+// checkAvailabilityBlocks rejects stays overlapping a block imported from another calendar
+func (hr *hotelRepository) checkAvailabilityBlocks(db *gorm.DB, roomID uint, checkIn, checkOut string) error {
+	var blocks int64
+
+	err := db.Model(&entity.AvailabilityBlock{}).
+		Where("room_id = ? AND start_date < ? AND end_date > ?", roomID, checkOut, checkIn).
+		Count(&blocks).Error
+	if err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	if blocks > 0 {
+		return fmt.Errorf("409 | Room is not available for these dates")
+	}
+
+	return nil
+}
+
+// resolvePromoCode validates a promo code against the stay and returns the discount it grants.
+// The promo row is locked so concurrent bookings cannot exceed its usage limits.
+func (hr *hotelRepository) resolvePromoCode(db *gorm.DB, code string, userID, hotelID uint, nights int, subTotal float64) (*entity.PromoCode, float64, error) {
+	var promo entity.PromoCode
+
+	result := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ? AND active = ?", code, true).First(&promo)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, 0, fmt.Errorf("400 | Promo code is not valid")
+		}
+
+		return nil, 0, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	now := time.Now()
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return nil, 0, fmt.Errorf("400 | Promo code is not active yet")
+	}
+
+	if promo.ValidUntil != nil && now.After(*promo.ValidUntil) {
+		return nil, 0, fmt.Errorf("400 | Promo code has expired")
+	}
+
+	if promo.MinNights > 0 && nights < promo.MinNights {
+		return nil, 0, fmt.Errorf("400 | Promo code requires a minimum stay of %d night(s)", promo.MinNights)
+	}
+
+	var hotelIDs []uint
+	if err := db.Table("promo_code_hotels").Where("promo_code_id = ?", promo.ID).Pluck("hotel_id", &hotelIDs).Error; err != nil {
+		return nil, 0, fmt.Errorf("500 | %v", err)
+	}
+
+	if len(hotelIDs) > 0 && !slices.Contains(hotelIDs, hotelID) {
+		return nil, 0, fmt.Errorf("400 | Promo code is not valid for this hotel")
+	}
+
+	activeStatuses := []string{"held", "redeemed"}
+
+	if promo.UsageLimit > 0 {
+		var used int64
+		if err := db.Model(&entity.PromoRedemption{}).Where("promo_code_id = ? AND status IN ?", promo.ID, activeStatuses).Count(&used).Error; err != nil {
+			return nil, 0, fmt.Errorf("500 | %v", err)
+		}
+
+		if used >= int64(promo.UsageLimit) {
+			return nil, 0, fmt.Errorf("400 | Promo code usage limit reached")
+		}
+	}
+
+	if promo.PerUserLimit > 0 && userID != 0 {
+		var used int64
+		if err := db.Model(&entity.PromoRedemption{}).Where("promo_code_id = ? AND user_id = ? AND status IN ?", promo.ID, userID, activeStatuses).Count(&used).Error; err != nil {
+			return nil, 0, fmt.Errorf("500 | %v", err)
+		}
+
+		if used >= int64(promo.PerUserLimit) {
+			return nil, 0, fmt.Errorf("400 | You have already used this promo code")
+		}
+	}
+
+	return &promo, utils.CalculateDiscount(promo, subTotal), nil
+}
+
+// CancelBookingRoom drops one room from a group booking and re-prices the rest.
+// Paid bookings are refunded the difference to the guest's wallet; cancelling the
+// last room cancels the whole booking.
+func (hr *hotelRepository) CancelBookingRoom(userID int, orderID string, lineID int) (*entity.Booking, float64, error) {
+	var booking entity.Booking
+	refund := 0.0
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", orderID).First(&booking)
+
+		if result.Error != nil {
+			if result.Error.Error() == "record not found" {
+				return fmt.Errorf("404 | Booking not found")
+			}
+
+			return fmt.Errorf("500 | %v", result.Error)
+		}
+
+		if booking.GuestID != uint(userID) {
+			return fmt.Errorf("401 | Unauthorized access")
+		}
+
+		if booking.BookingStatus != "pending" && booking.BookingStatus != "settlement" {
+			return fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
+		}
+
+		checkIn, _ := time.Parse("2006-01-02", booking.CheckIn[:10])
+		if !time.Now().Before(checkIn) {
+			return fmt.Errorf("400 | Rooms can only be cancelled before check-in")
+		}
+
+		// A pending gateway charge, including one for the balance, has a fixed amount and cannot be re-priced
+		if pendingPayment := tx.Where("order_id = ? AND payment_status = ?", booking.OrderID, "pending").First(&entity.Payment{}); pendingPayment.RowsAffected > 0 {
+			return fmt.Errorf("400 | Booking already has a pending payment")
+		}
+
+		var rooms []entity.BookingRoom
+		if err := tx.Where("booking_id = ? AND status = ?", booking.ID, "active").Order("id").Find(&rooms).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		var cancelled *entity.BookingRoom
+		remaining := make([]entity.BookingRoom, 0, len(rooms))
+		for i := range rooms {
+			if rooms[i].ID == uint(lineID) {
+				cancelled = &rooms[i]
+			} else {
+				remaining = append(remaining, rooms[i])
+			}
+		}
+
+		if cancelled == nil {
+			return fmt.Errorf("404 | Room not found in booking")
+		}
+
+		wasPaid := booking.BookingStatus == "settlement"
+
+		if len(remaining) == 0 {
+			booking.BookingStatus = "cancel"
+
+			if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ? AND status IN ?", booking.OrderID, []string{"held", "redeemed"}).Update("status", "released").Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+		} else if err := hr.repriceBooking(tx, &booking, remaining); err != nil {
+			return err
+		}
+
+		// Only what was paid beyond the new total comes back, a deposit may still be owed
+		if wasPaid {
+			refund = utils.RoundPrice(booking.AmountPaid - booking.TotalPrice)
+			if len(remaining) == 0 {
+				refund = booking.AmountPaid
+			}
+
+			refund = max(refund, 0)
+			booking.AmountPaid = utils.RoundPrice(booking.AmountPaid - refund)
+
+			if refund > 0 {
+				if err := adjustBalance(tx, booking.GuestID, "booking_refund", refund, booking.OrderID, "Refund for a cancelled room of booking "+booking.BookingCode); err != nil {
+					return err
+				}
+			}
+		}
+
+		cancelled.Status = "cancelled"
+		cancelled.RefundAmount = refund
+		if err := tx.Save(cancelled).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&booking).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if booking.BookingStatus == "cancel" {
+			if err := queueBookingEmail(tx, booking, utils.EmailBookingCancelled); err != nil {
+				return err
+			}
+
+			return queueWebhookEvent(tx, utils.WebhookBookingCancelled, booking)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hr.DB.Preload("Rooms").Preload("Charges").First(&booking, booking.ID)
+
+	return &booking, refund, nil
+}
+
+// AssignBookingRoom puts a specific room on a booking line, typically one booked by room type
+func (hr *hotelRepository) AssignBookingRoom(orderID string, payload entity.AssignBookingRoomPayload) (*entity.BookingRoom, error) {
+	var line entity.BookingRoom
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		var booking entity.Booking
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", orderID).First(&booking)
+
+		if result.Error != nil {
+			if result.Error.Error() == "record not found" {
+				return fmt.Errorf("404 | Booking not found")
+			}
+
+			return fmt.Errorf("500 | %v", result.Error)
+		}
+
+		if booking.BookingStatus != "pending" && booking.BookingStatus != "settlement" {
+			return fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
+		}
+
+		assigned, err := hr.assignBookingRoom(tx, &booking, payload)
+		if err != nil {
+			return err
+		}
+
+		line = *assigned
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &line, nil
+}
+
+// assignBookingRoom puts a free room of the booked type on one line of the booking
+func (hr *hotelRepository) assignBookingRoom(tx *gorm.DB, booking *entity.Booking, payload entity.AssignBookingRoomPayload) (*entity.BookingRoom, error) {
+	var line entity.BookingRoom
+
+	if err := tx.Where("id = ? AND booking_id = ? AND status = ?", payload.BookingRoomID, booking.ID, "active").First(&line).Error; err != nil {
+		return nil, fmt.Errorf("404 | Booking room not found")
+	}
+
+	// Locked so two lines cannot be given the same room at once
+	var room entity.Room
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("hotel_id = ? AND id = ?", booking.HotelID, payload.RoomID).First(&room).Error; err != nil {
+		return nil, fmt.Errorf("404 | Room not found")
+	}
+
+	if line.RoomTypeID != nil && (room.RoomTypeID == nil || *room.RoomTypeID != *line.RoomTypeID) {
+		return nil, fmt.Errorf("400 | Room is not of the booked room type")
+	}
+
+	if err := hr.checkRoomBookings(tx, room.ID, line.ID, booking.CheckIn[:10], booking.CheckOut[:10]); err != nil {
+		return nil, err
+	}
+
+	if err := hr.checkAvailabilityBlocks(tx, room.ID, booking.CheckIn[:10], booking.CheckOut[:10]); err != nil {
+		return nil, err
+	}
+
+	line.RoomID = room.ID
+	line.RoomNumber = room.RoomNumber
+	if err := tx.Save(&line).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if booking.RoomID == 0 {
+		if err := tx.Model(booking).Update("room_id", room.ID).Error; err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	return &line, nil
+}
+
+// CheckIn records the guest's ID, assigns any rooms still missing and occupies
+// the rooms. Paid bookings can be checked in from their check-in day until the
+// day before check-out.
+func (hr *hotelRepository) CheckIn(staffID int, bookingCode string, payload entity.CheckInPayload) (*entity.Booking, error) {
+	var booking entity.Booking
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := hr.lockBookingByCode(tx, bookingCode, &booking); err != nil {
+			return err
+		}
+
+		switch booking.BookingStatus {
+		case "settlement":
+		case "pending":
+			return fmt.Errorf("400 | Booking has not been paid")
+		default:
+			return fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
+		}
+
+		today := time.Now().Format("2006-01-02")
+		if today < booking.CheckIn[:10] {
+			return fmt.Errorf("400 | Check-in opens on %s", booking.CheckIn[:10])
+		}
+
+		if today >= booking.CheckOut[:10] {
+			return fmt.Errorf("400 | Stay has already ended")
+		}
+
+		for _, assignment := range payload.Rooms {
+			if _, err := hr.assignBookingRoom(tx, &booking, assignment); err != nil {
+				return err
+			}
+		}
+
+		var unassigned int64
+		if err := tx.Model(&entity.BookingRoom{}).Where("booking_id = ? AND status = ? AND room_id = 0", booking.ID, "active").Count(&unassigned).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if unassigned > 0 {
+			return fmt.Errorf("400 | Every room must be assigned before check-in")
+		}
+
+		roomIDs, err := hr.stayRoomIDs(tx, &booking)
+		if err != nil {
+			return err
+		}
+
+		var rooms []entity.Room
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", roomIDs).Find(&rooms).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		for _, room := range rooms {
+			// The previous guest may not have checked out yet
+			if room.Status == "occupied" {
+				return fmt.Errorf("409 | Room %s is still occupied", room.RoomNumber)
+			}
+
+			if room.HousekeepingStatus == "out_of_order" {
+				return fmt.Errorf("409 | Room %s is out of order", room.RoomNumber)
+			}
+
+			if room.HousekeepingStatus == "dirty" {
+				return fmt.Errorf("409 | Room %s has not been cleaned", room.RoomNumber)
+			}
+		}
+
+		// A deposit booking settles its balance before the keys are handed over
+		if balance := utils.RoundPrice(booking.TotalPrice - booking.AmountPaid); balance > 0 {
+			if err := hr.collectBalance(tx, &booking, balance, payload.BalancePaymentMethod); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entity.Room{}).Where("id IN ?", roomIDs).Update("status", "occupied").Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		now := time.Now()
+		err = tx.Model(&booking).Updates(map[string]interface{}{
+			"booking_status":  "checked_in",
+			"guest_id_type":   payload.IDType,
+			"guest_id_number": payload.IDNumber,
+			"checked_in_at":   now,
+			"checked_in_by":   staffID,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	// Get room
-	room, err := hr.getHotelRoom(uint(hotelID), request.RoomID)
+	return hr.getBookingWithRooms(booking.ID)
+}
+
+// CheckOut releases the rooms of an in-house booking for housekeeping and
+// bills the late checkout fee collected at the desk, if any
+func (hr *hotelRepository) CheckOut(bookingCode string, payload entity.CheckOutPayload) (*entity.Booking, error) {
+	var booking entity.Booking
+
+	fee := utils.RoundPrice(payload.LateCheckoutFee)
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := hr.lockBookingByCode(tx, bookingCode, &booking); err != nil {
+			return err
+		}
+
+		if booking.BookingStatus != "checked_in" {
+			return fmt.Errorf("400 | Booking is not checked in")
+		}
+
+		roomIDs, err := hr.stayRoomIDs(tx, &booking)
+		if err != nil {
+			return err
+		}
+
+		// Rooms go back on sale but need cleaning before the next guest
+		err = tx.Model(&entity.Room{}).Where("id IN ?", roomIDs).Updates(map[string]interface{}{
+			"status":                  "Available",
+			"housekeeping_status":     "dirty",
+			"housekeeping_updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if fee > 0 {
+			if err := hr.chargeLateCheckout(tx, &booking, fee, payload.PaymentMethod); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&booking).Updates(map[string]interface{}{
+			"booking_status":    "checked_out",
+			"checked_out_at":    time.Now(),
+			"late_checkout_fee": fee,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+
 	if err != nil {
 		return nil, err
 	}
 
-	// Get user
-	user, err := hr.getUserByID(uint(userID))
+	return hr.getBookingWithRooms(booking.ID)
+}
+
+// chargeLateCheckout adds the late checkout fee to the booking as a charge
+// line, on its invoice as well, and records it as paid at the desk
+func (hr *hotelRepository) chargeLateCheckout(tx *gorm.DB, booking *entity.Booking, fee float64, method string) error {
+	charge := entity.BookingCharge{
+		BookingID:  booking.ID,
+		Name:       "Late checkout",
+		ChargeType: "fixed",
+		Rate:       fee,
+		Basis:      "per_stay",
+		Amount:     fee,
+	}
+
+	if err := tx.Create(&charge).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	booking.TotalPrice = utils.RoundPrice(booking.TotalPrice + fee)
+	if err := tx.Model(booking).Update("total_price", booking.TotalPrice).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	// The invoice was issued when the stay was paid, the fee is billed on it too
+	var invoice entity.Invoice
+	if result := tx.Where("booking_id = ?", booking.ID).First(&invoice); result.RowsAffected > 0 {
+		invoice.Charges = append(invoice.Charges, charge)
+		invoice.TotalPrice = utils.RoundPrice(invoice.TotalPrice + fee)
+
+		if err := tx.Save(&invoice).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	return hr.collectBalance(tx, booking, utils.RoundPrice(booking.TotalPrice-booking.AmountPaid), method)
+}
+
+// MarkNoShow closes a paid booking whose guest never arrived. It is only
+// allowed once the check-in day has passed.
+func (hr *hotelRepository) MarkNoShow(bookingCode string) (*entity.Booking, error) {
+	var booking entity.Booking
+
+	err := hr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := hr.lockBookingByCode(tx, bookingCode, &booking); err != nil {
+			return err
+		}
+
+		if booking.BookingStatus != "settlement" {
+			return fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
+		}
+
+		if time.Now().Format("2006-01-02") <= booking.CheckIn[:10] {
+			return fmt.Errorf("400 | Guest can still arrive today")
+		}
+
+		if err := tx.Model(&booking).Update("booking_status", "no_show").Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	orderID := fmt.Sprintf("BKNG-%d%s", userID, uuid.New().String())
-	bookingCode := fmt.Sprintf("%s%d%d", time.Now().Format("20060102"), hotelID, request.RoomID)
-	room.Price = utils.AdjustPrice(room.Price, checkIn)
-	totalPrice := float64(totalDays) * room.Price
+	return hr.getBookingWithRooms(booking.ID)
+}
+
+// collectBalance records the outstanding balance of a booking as paid at the desk
+func (hr *hotelRepository) collectBalance(tx *gorm.DB, booking *entity.Booking, balance float64, method string) error {
+	if method == "" {
+		return fmt.Errorf("400 | Outstanding balance of %.2f must be collected at check-in", balance)
+	}
+
+	// The guest could still complete the online payment as well
+	if pending := tx.Where("order_id = ? AND payment_status = ?", booking.OrderID, "pending").First(&entity.Payment{}); pending.RowsAffected > 0 {
+		return fmt.Errorf("409 | Balance is being paid online, wait for the payment to complete or expire")
+	}
+
+	paymentDate, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	payment := entity.Payment{
+		PaymentID:       newPaymentID(),
+		OrderID:         booking.OrderID,
+		UserID:          booking.GuestID,
+		TotalAmount:     balance,
+		TransactionType: "hotel booking",
+		Installment:     "balance",
+		PaymentDate:     &paymentDate,
+		PaymentStatus:   "settlement",
+		PaymentMethod:   "desk - " + method,
+	}
+
+	if err := tx.Create(&payment).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	booking.AmountPaid = booking.TotalPrice
+	if err := tx.Model(booking).Update("amount_paid", booking.AmountPaid).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	return nil
+}
+
+// lockBookingByCode loads the booking behind a code given at the front desk
+func (hr *hotelRepository) lockBookingByCode(tx *gorm.DB, bookingCode string, booking *entity.Booking) error {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("booking_code = ?", bookingCode)
+
+	// Date based codes were not unique, the latest booking under one is meant
+	if utils.LegacyBookingCode(bookingCode) {
+		query = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("legacy_booking_code = ?", bookingCode).Order("id DESC")
+	}
+
+	result := query.First(booking)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return fmt.Errorf("404 | Booking not found")
+		}
+
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return nil
+}
+
+// stayRoomIDs returns the rooms a booking occupies during the stay
+func (hr *hotelRepository) stayRoomIDs(tx *gorm.DB, booking *entity.Booking) ([]uint, error) {
+	var roomIDs []uint
+
+	if err := tx.Model(&entity.BookingRoom{}).Where("booking_id = ? AND status = ? AND room_id <> 0", booking.ID, "active").Pluck("room_id", &roomIDs).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	// Bookings made before multi-room orders only carry room_id
+	if len(roomIDs) == 0 && booking.RoomID != 0 {
+		roomIDs = append(roomIDs, booking.RoomID)
+	}
 
-	booking := hr.createBookingEntity(orderID, bookingCode, *user, *hotel, *room, checkIn, checkOut, totalDays, totalPrice)
+	return roomIDs, nil
+}
 
-	insertBooking := hr.DB.Save(&booking)
+func (hr *hotelRepository) getBookingWithRooms(bookingID uint) (*entity.Booking, error) {
+	var booking entity.Booking
 
-	if insertBooking.Error != nil {
-		return nil, fmt.Errorf("500 | %v", insertBooking.Error)
+	if err := hr.DB.Preload("Rooms").First(&booking, bookingID).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
 	}
 
 	return &booking, nil
 }
 
+func (hr *hotelRepository) GetBookingDetail(userID int, orderID string) (*entity.BookingDetailResponse, error) {
+	var booking entity.Booking
+
+	result := hr.DB.Preload("Rooms").Preload("Charges").Where("order_id = ?", orderID).First(&booking)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Booking not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if booking.GuestID != uint(userID) {
+		return nil, fmt.Errorf("401 | Unauthorized access")
+	}
+
+	hotel, err := hr.getHotelByID(int(booking.HotelID))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := hr.getUserByID(booking.GuestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bookings made before multi-room orders only carry room_id
+	rooms := booking.Rooms
+	if len(rooms) == 0 {
+		var room entity.Room
+		hr.DB.First(&room, booking.RoomID)
+
+		rooms = []entity.BookingRoom{{
+			RoomID:     booking.RoomID,
+			RoomNumber: room.RoomNumber,
+			RoomRate:   room.Price,
+			SubTotal:   booking.TotalPrice,
+			Status:     "active",
+		}}
+	}
+
+	var payments []entity.Payment
+	if err := hr.DB.Where("order_id = ?", booking.OrderID).Order("created_at DESC").Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	detail := entity.BookingDetailResponse{
+		OrderID:       booking.OrderID,
+		BookingCode:   booking.BookingCode,
+		BookingStatus: booking.BookingStatus,
+		CheckIn:       booking.CheckIn[:10],
+		CheckOut:      booking.CheckOut[:10],
+		TotalDays:     booking.TotalDays,
+		Hotel: entity.BookingHotelDetail{
+			ID:            hotel.ID,
+			Name:          hotel.Name,
+			Location:      hotel.Location,
+			ContactNumber: hotel.ContactNumber,
+			Email:         hotel.Email,
+			CheckInTime:   hotel.CheckInTime,
+			CheckOutTime:  hotel.CheckOutTime,
+		},
+		Guest: entity.BookingGuestDetail{
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Email:       user.Email,
+			PhoneNumber: user.PhoneNumber,
+		},
+		Rooms:          rooms,
+		SubTotal:       booking.SubTotal,
+		PromoCode:      booking.PromoCode,
+		Discount:       booking.Discount,
+		Charges:        booking.Charges,
+		TotalPrice:     booking.TotalPrice,
+		DepositAmount:  booking.DepositAmount,
+		AmountPaid:     booking.AmountPaid,
+		BalanceDueDate: booking.BalanceDueDate,
+		Payments:       make([]entity.BookingPaymentDetail, 0, len(payments)),
+		CreatedAt:      booking.CreatedAt,
+	}
+
+	// Nothing is due on bookings that were cancelled or expired
+	if booking.BookingStatus == "pending" || booking.BookingStatus == "settlement" || booking.BookingStatus == "checked_in" {
+		detail.BalanceDue = max(utils.RoundPrice(booking.TotalPrice-booking.AmountPaid), 0)
+	}
+
+	for _, payment := range payments {
+		detail.Payments = append(detail.Payments, entity.BookingPaymentDetail{
+			PaymentID:     payment.PaymentID,
+			PaymentMethod: payment.PaymentMethod,
+			Bank:          payment.Bank,
+			VANumber:      payment.VANumber,
+			PaymentStatus: payment.PaymentStatus,
+			Installment:   payment.Installment,
+			Amount:        payment.TotalAmount,
+			PaymentDate:   payment.PaymentDate,
+		})
+	}
+
+	if len(payments) > 0 {
+		detail.Payment = &detail.Payments[0]
+	}
+
+	detail.Timeline = hr.bookingTimeline(booking, booking.Rooms, payments)
+
+	return &detail, nil
+}
+
+// bookingTimeline rebuilds what happened to a booking, oldest first
+func (hr *hotelRepository) bookingTimeline(booking entity.Booking, rooms []entity.BookingRoom, payments []entity.Payment) []entity.BookingTimelineEvent {
+	timeline := []entity.BookingTimelineEvent{{
+		Event:       "booked",
+		Description: "Booking created",
+		Time:        booking.CreatedAt,
+	}}
+
+	for _, payment := range payments {
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "payment_started",
+			Description: "Payment started with " + payment.PaymentMethod,
+			Time:        payment.CreatedAt,
+		})
+
+		if payment.PaymentStatus == "settlement" {
+			event := entity.BookingTimelineEvent{
+				Event:       "paid",
+				Description: "Payment received",
+				Time:        payment.UpdatedAt,
+			}
+
+			switch payment.Installment {
+			case "deposit":
+				event.Event = "deposit_paid"
+				event.Description = "Deposit received"
+			case "balance":
+				event.Description = "Balance received"
+			}
+
+			timeline = append(timeline, event)
+		}
+	}
+
+	for _, room := range rooms {
+		if room.Status == "cancelled" {
+			timeline = append(timeline, entity.BookingTimelineEvent{
+				Event:       "room_cancelled",
+				Description: fmt.Sprintf("Room %s cancelled, refund %.2f", room.RoomNumber, room.RefundAmount),
+				Time:        room.UpdatedAt,
+			})
+		}
+	}
+
+	if booking.CheckedInAt != nil {
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "checked_in",
+			Description: "Guest checked in",
+			Time:        *booking.CheckedInAt,
+		})
+	}
+
+	if booking.CheckedOutAt != nil {
+		description := "Guest checked out"
+		if booking.LateCheckoutFee > 0 {
+			description = fmt.Sprintf("Guest checked out, late checkout fee %.2f", booking.LateCheckoutFee)
+		}
+
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "checked_out",
+			Description: description,
+			Time:        *booking.CheckedOutAt,
+		})
+	}
+
+	switch booking.BookingStatus {
+	case "no_show":
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "no_show",
+			Description: "Guest did not arrive",
+			Time:        booking.UpdatedAt,
+		})
+	case "expire":
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "expired",
+			Description: "Payment window expired",
+			Time:        booking.UpdatedAt,
+		})
+	case "cancel":
+		description := "Booking cancelled"
+		if booking.BalanceDueDate != nil && booking.AmountPaid > 0 && booking.AmountPaid < booking.TotalPrice {
+			description = "Booking cancelled, balance was not paid by " + booking.BalanceDueDate.Format("2006-01-02")
+		}
+
+		timeline = append(timeline, entity.BookingTimelineEvent{
+			Event:       "cancelled",
+			Description: description,
+			Time:        booking.UpdatedAt,
+		})
+	}
+
+	slices.SortStableFunc(timeline, func(a, b entity.BookingTimelineEvent) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return timeline
+}
+
+// repriceBooking recalculates a booking for its remaining rooms using the
+// charge rates and promo it was originally priced with
+func (hr *hotelRepository) repriceBooking(tx *gorm.DB, booking *entity.Booking, rooms []entity.BookingRoom) error {
+	var oldCharges []entity.BookingCharge
+	if err := tx.Where("booking_id = ?", booking.ID).Order("id").Find(&oldCharges).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	subTotal := 0.0
+	for _, room := range rooms {
+		subTotal += room.SubTotal
+	}
+	subTotal = utils.RoundPrice(subTotal)
+
+	discount := 0.0
+	if booking.PromoCode != "" {
+		var promo entity.PromoCode
+		if err := tx.Where("code = ?", booking.PromoCode).First(&promo).Error; err == nil {
+			discount = utils.CalculateDiscount(promo, subTotal)
+		}
+
+		if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ?", booking.OrderID).Update("discount_amount", discount).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	charges, chargesTotal := utils.CalculateCharges(subTotal-discount, booking.TotalDays*len(rooms), utils.ChargeRules(oldCharges))
+
+	if err := tx.Where("booking_id = ?", booking.ID).Delete(&entity.BookingCharge{}).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	for i := range charges {
+		charges[i].BookingID = booking.ID
+	}
+
+	if len(charges) > 0 {
+		if err := tx.Create(&charges).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	booking.SubTotal = subTotal
+	booking.Discount = discount
+	booking.TotalPrice = utils.RoundPrice(subTotal - discount + chargesTotal)
+	booking.RoomID = rooms[0].RoomID
+
+	// A deposit not paid yet follows the new total
+	if booking.BookingStatus == "pending" && booking.DepositPercent > 0 {
+		booking.DepositAmount = utils.DepositAmount(booking.TotalPrice, booking.DepositPercent)
+	}
+
+	return nil
+}
+
 func (hr *hotelRepository) parseBookingDates(checkInStr, checkOutStr string) (time.Time, time.Time, error) {
 	checkIn, err := time.Parse("2006-01-02", checkInStr)
 	if err != nil {
@@ -166,10 +1459,10 @@ func (hr *hotelRepository) getHotelByID(hotelID int) (*entity.Hotel, error) {
 	return &hotel, nil
 }
 
-func (hr *hotelRepository) getHotelRoom(hotelID, roomID uint) (*entity.Room, error) {
+func (hr *hotelRepository) getHotelRoom(db *gorm.DB, hotelID, roomID uint) (*entity.Room, error) {
 	var room entity.Room
 
-	result := hr.DB.Where("hotel_id = ? AND id = ?", hotelID, roomID).First(&room)
+	result := db.Preload("Type").Where("hotel_id = ? AND id = ?", hotelID, roomID).First(&room)
 
 	if result.Error != nil {
 		if result.Error.Error() == "record not found" {
@@ -179,24 +1472,78 @@ func (hr *hotelRepository) getHotelRoom(hotelID, roomID uint) (*entity.Room, err
 		return nil, fmt.Errorf("500 | %v", result.Error)
 	}
 
-	if room.Status == "occupied" {
-		return nil, fmt.Errorf("400 | Room is occupied")
+	return &room, nil
+}
+
+func (hr *hotelRepository) getHotelCharges(hotelID uint) ([]entity.HotelCharge, error) {
+	var charges []entity.HotelCharge
+
+	result := hr.DB.Where("hotel_id = ? AND active = ?", hotelID, true).Order("sort_order, id").Find(&charges)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
 	}
 
-	return &room, nil
+	return charges, nil
+}
+
+// insertBooking saves a new booking under a fresh booking code. A code that is
+// already taken is caught by the unique index and another one is drawn.
+func (hr *hotelRepository) insertBooking(tx *gorm.DB, booking *entity.Booking) error {
+	for attempt := 1; ; attempt++ {
+		code, err := utils.GenerateBookingCode()
+		if err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		booking.BookingCode = code
+
+		// A failed insert aborts the transaction unless rolled back to here
+		if err := tx.SavePoint("booking_code").Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		err = tx.Create(booking).Error
+		if err == nil {
+			return nil
+		}
+
+		if !strings.Contains(err.Error(), "idx_bookings_booking_code") || attempt == maxBookingCodeAttempts {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if err := tx.RollbackTo("booking_code").Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		booking.ID = 0
+	}
 }
 
-func (hr *hotelRepository) createBookingEntity(orderID string, bookingCode string, user entity.User, hotel entity.Hotel, room entity.Room, checkIn time.Time, checkOut time.Time, totalDays int, totalPrice float64) entity.Booking {
-	return entity.Booking{
-		OrderID:       orderID,
-		BookingCode:   bookingCode,
-		GuestID:       user.UserID,
-		HotelID:       hotel.ID,
-		RoomID:        room.ID,
-		CheckIn:       checkIn.Format("2006-01-02"),
-		CheckOut:      checkOut.Format("2006-01-02"),
-		TotalDays:     totalDays,
-		TotalPrice:    totalPrice,
-		BookingStatus: "pending",
+func (hr *hotelRepository) createBookingEntity(orderID string, user entity.User, quote entity.BookingQuote) entity.Booking {
+	booking := entity.Booking{
+		OrderID:        orderID,
+		GuestID:        user.UserID,
+		HotelID:        quote.HotelID,
+		RoomID:         quote.Rooms[0].RoomID,
+		CheckIn:        quote.CheckIn,
+		CheckOut:       quote.CheckOut,
+		TotalDays:      quote.TotalDays,
+		SubTotal:       quote.SubTotal,
+		PromoCode:      quote.PromoCode,
+		Discount:       quote.Discount,
+		TotalPrice:     quote.TotalPrice,
+		DepositPercent: quote.DepositPercent,
+		DepositAmount:  quote.DepositAmount,
+		BookingStatus:  "pending",
+		Rooms:          quote.Rooms,
+		Charges:        quote.Charges,
+	}
+
+	if quote.BalanceDueDate != "" {
+		dueDate, _ := time.Parse("2006-01-02", quote.BalanceDueDate)
+		booking.BalanceDueDate = &dueDate
 	}
+
+	return booking
 }
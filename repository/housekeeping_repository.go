@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Housekeeping statuses a room can move to from each status
+var housekeepingTransitions = map[string][]string{
+	"dirty":        {"clean", "out_of_order"},
+	"clean":        {"dirty", "inspected", "out_of_order"},
+	"inspected":    {"dirty", "out_of_order"},
+	"out_of_order": {"dirty", "clean"},
+}
+
+type HousekeepingRepository interface {
+	UpdateRoomHousekeeping(roomID int, payload entity.HousekeepingPayload) (*entity.Room, error)
+	GetHousekeepingTasks(hotelID int, date string) (*entity.HousekeepingTaskList, error)
+}
+
+type housekeepingRepository struct {
+	DB *gorm.DB
+}
+
+func NewHousekeepingRepository(db *gorm.DB) HousekeepingRepository {
+	return &housekeepingRepository{DB: db}
+}
+
+func (hr *housekeepingRepository) UpdateRoomHousekeeping(roomID int, payload entity.HousekeepingPayload) (*entity.Room, error) {
+	var room entity.Room
+
+	result := hr.DB.First(&room, roomID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Room not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	// Keeping the same status only updates the note
+	if payload.Status != room.HousekeepingStatus && !slices.Contains(housekeepingTransitions[room.HousekeepingStatus], payload.Status) {
+		return nil, fmt.Errorf("400 | Room cannot go from %s to %s", room.HousekeepingStatus, payload.Status)
+	}
+
+	now := time.Now()
+	room.HousekeepingStatus = payload.Status
+	room.HousekeepingNote = payload.Note
+	room.HousekeepingUpdatedAt = &now
+
+	err := hr.DB.Model(&room).Updates(map[string]interface{}{
+		"housekeeping_status":     room.HousekeepingStatus,
+		"housekeeping_note":       room.HousekeepingNote,
+		"housekeeping_updated_at": room.HousekeepingUpdatedAt,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	return &room, nil
+}
+
+// GetHousekeepingTasks lists the rooms of a hotel that need work on a day.
+// Rooms with an arrival that day come first.
+func (hr *housekeepingRepository) GetHousekeepingTasks(hotelID int, date string) (*entity.HousekeepingTaskList, error) {
+	var hotel entity.Hotel
+
+	if result := hr.DB.First(&hotel, hotelID); result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Hotel not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	var rooms []entity.Room
+	if err := hr.DB.Where("hotel_id = ?", hotel.ID).Order("room_number").Find(&rooms).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	var bookings []entity.Booking
+	err := hr.DB.Preload("Rooms", "status = ?", "active").
+		Where("hotel_id = ? AND booking_status IN ? AND check_in <= ? AND check_out >= ?", hotel.ID, []string{"settlement", "checked_in"}, date, date).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	arrivals := map[uint]bool{}
+	departures := map[uint]bool{}
+	stayovers := map[uint]bool{}
+
+	for _, booking := range bookings {
+		roomIDs := []uint{}
+		for _, line := range booking.Rooms {
+			if line.RoomID != 0 {
+				roomIDs = append(roomIDs, line.RoomID)
+			}
+		}
+
+		// Bookings made before multi-room orders only carry room_id
+		if len(booking.Rooms) == 0 && booking.RoomID != 0 {
+			roomIDs = append(roomIDs, booking.RoomID)
+		}
+
+		for _, roomID := range roomIDs {
+			switch {
+			case booking.CheckIn[:10] == date:
+				arrivals[roomID] = true
+			case booking.CheckOut[:10] == date:
+				departures[roomID] = true
+			default:
+				stayovers[roomID] = true
+			}
+		}
+	}
+
+	tasks := []entity.HousekeepingTask{}
+
+	for _, room := range rooms {
+		task := entity.HousekeepingTask{
+			RoomID:             room.ID,
+			RoomNumber:         room.RoomNumber,
+			HousekeepingStatus: room.HousekeepingStatus,
+			Note:               room.HousekeepingNote,
+			Occupied:           room.Status == "occupied",
+			Arrival:            arrivals[room.ID],
+			Departure:          departures[room.ID],
+			Priority:           "normal",
+		}
+
+		switch {
+		case room.HousekeepingStatus == "out_of_order":
+			task.Task = "maintenance"
+		case task.Departure || room.HousekeepingStatus == "dirty":
+			task.Task = "clean"
+		case stayovers[room.ID]:
+			task.Task = "service"
+		case task.Arrival && room.HousekeepingStatus == "clean":
+			task.Task = "inspect"
+		default:
+			continue
+		}
+
+		if task.Arrival {
+			task.Priority = "high"
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	slices.SortStableFunc(tasks, func(a, b entity.HousekeepingTask) int {
+		if a.Priority == b.Priority {
+			return 0
+		}
+
+		if a.Priority == "high" {
+			return -1
+		}
+
+		return 1
+	})
+
+	return &entity.HousekeepingTaskList{
+		HotelID: hotel.ID,
+		Date:    date,
+		Tasks:   tasks,
+	}, nil
+}
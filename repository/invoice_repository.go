@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Booking statuses reached only after payment
+var paidBookingStatuses = []string{"settlement", "checked_in", "checked_out", "no_show"}
+
+type InvoiceRepository interface {
+	GetInvoice(userID int, role string, orderID string) (*entity.InvoiceDocument, error)
+}
+
+type invoiceRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{DB: db}
+}
+
+func (ir *invoiceRepository) GetInvoice(userID int, role string, orderID string) (*entity.InvoiceDocument, error) {
+	var document entity.InvoiceDocument
+
+	result := ir.DB.Where("order_id = ?", orderID).First(&document.Booking)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Booking not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if document.Booking.GuestID != uint(userID) && role != "admin" {
+		return nil, fmt.Errorf("401 | Unauthorized access")
+	}
+
+	if !slices.Contains(paidBookingStatuses, document.Booking.BookingStatus) {
+		return nil, fmt.Errorf("400 | Invoice is only available for paid bookings")
+	}
+
+	// Bookings settled before invoicing existed get their number on first download
+	invoice, err := issueInvoice(ir.DB, &document.Booking)
+	if err != nil {
+		return nil, err
+	}
+	document.Invoice = *invoice
+
+	if err := ir.DB.First(&document.Hotel, document.Booking.HotelID).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if err := ir.DB.Where("user_id = ?", document.Booking.GuestID).First(&document.Guest).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if err := ir.DB.Where("order_id = ? AND payment_status = ?", orderID, "settlement").Order("id").Find(&document.Payments).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	return &document, nil
+}
+
+// issueInvoice gives a settled booking the next invoice number of its hotel.
+// A booking keeps the invoice it was first issued, so calling it again is safe.
+func issueInvoice(db *gorm.DB, booking *entity.Booking) (*entity.Invoice, error) {
+	var invoice entity.Invoice
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Locking the hotel serialises numbering within it
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity.Hotel{}, booking.HotelID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if result := tx.Where("booking_id = ?", booking.ID).First(&invoice); result.RowsAffected > 0 {
+			// Invoices issued before lines were stored keep what the booking shows at first download
+			if invoice.TotalPrice == 0 && len(invoice.Rooms) == 0 {
+				if err := snapshotInvoice(tx, &invoice, booking); err != nil {
+					return err
+				}
+
+				if err := tx.Save(&invoice).Error; err != nil {
+					return fmt.Errorf("500 | %v", err)
+				}
+			}
+
+			return nil
+		}
+
+		var lastSequence int
+		if err := tx.Model(&entity.Invoice{}).Where("hotel_id = ?", booking.HotelID).Select("COALESCE(MAX(sequence), 0)").Scan(&lastSequence).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		invoice = entity.Invoice{
+			InvoiceNumber: fmt.Sprintf("INV-%03d-%06d", booking.HotelID, lastSequence+1),
+			HotelID:       booking.HotelID,
+			Sequence:      lastSequence + 1,
+			BookingID:     booking.ID,
+			OrderID:       booking.OrderID,
+			IssuedAt:      time.Now(),
+		}
+
+		if err := snapshotInvoice(tx, &invoice, booking); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&invoice).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+// snapshotInvoice copies the booking's active lines and totals onto the invoice
+func snapshotInvoice(tx *gorm.DB, invoice *entity.Invoice, booking *entity.Booking) error {
+	if err := tx.Where("booking_id = ? AND status = ?", booking.ID, "active").Order("id").Find(&invoice.Rooms).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	// Lines booked before the type name was kept on them
+	for i := range invoice.Rooms {
+		if invoice.Rooms[i].RoomTypeName == "" && invoice.Rooms[i].RoomTypeID != nil {
+			if err := tx.Model(&entity.RoomType{}).Select("name").Where("id = ?", *invoice.Rooms[i].RoomTypeID).Scan(&invoice.Rooms[i].RoomTypeName).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+		}
+	}
+
+	if err := tx.Where("booking_id = ?", booking.ID).Order("id").Find(&invoice.Charges).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	invoice.SubTotal = booking.SubTotal
+	invoice.PromoCode = booking.PromoCode
+	invoice.Discount = booking.Discount
+	invoice.TotalPrice = booking.TotalPrice
+
+	// Bookings made before itemised pricing only have a total
+	if len(invoice.Rooms) == 0 {
+		invoice.SubTotal = booking.TotalPrice
+	}
+
+	return nil
+}
@@ -1,11 +1,18 @@
 package repository
 
 import (
+	"log"
 	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"slices"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Midtrans statuses that end a payment attempt without payment
+var closedPaymentStatuses = []string{"expire", "cancel", "deny", "failure"}
+
 type MidtransRepository interface {
 	HandleTopUpCallback(entity.MidtransCallbackResponse)
 	HandleBookingCallback(entity.MidtransCallbackResponse)
@@ -20,53 +27,238 @@ func NewMidtransRepository(db *gorm.DB) MidtransRepository {
 }
 
 func (mr *midtransRepository) HandleTopUpCallback(payload entity.MidtransCallbackResponse) {
+	payload.TransactionStatus = callbackStatus(payload)
+
 	if payload.TransactionStatus == "settlement" {
-		var user entity.User
-		var transaction entity.TopUpTransaction
-		var payment entity.Payment
+		err := mr.DB.Transaction(func(tx *gorm.DB) error {
+			var transaction entity.TopUpTransaction
+
+			payment, err := mr.findPayment(tx, payload.OrderID)
+			if err != nil {
+				return err
+			}
+
+			// Midtrans repeats notifications, credit the balance only once
+			if payment.PaymentStatus == "settlement" {
+				return nil
+			}
+
+			if err := tx.Model(payment).Updates(map[string]interface{}{
+				"payment_status": "settlement",
+				"payment_date":   payload.TransactionTime,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("order_id = ?", payment.OrderID).First(&transaction).Error; err != nil {
+				return err
+			}
+
+			if transaction.TransactionStatus == "settlement" {
+				log.Printf("Payment %s settled for top-up %s which was already paid", payment.PaymentID, transaction.OrderID)
+				return nil
+			}
 
-		mr.DB.Where("order_id = ?", payload.OrderID).First(&transaction)
-		mr.DB.Model(&user).Where("user_id = ?", transaction.UserID)
+			if err := adjustBalance(tx, transaction.UserID, "top_up", transaction.Amount, transaction.OrderID, "Balance top-up"); err != nil {
+				return err
+			}
 
-		newBalance := user.Balance + transaction.Amount
-		mr.DB.Model(&user).Update("balance", newBalance)
+			if err := tx.Model(&transaction).Where("order_id = ?", transaction.OrderID).Update("transaction_status", "settlement").Error; err != nil {
+				return err
+			}
 
-		mr.DB.Model(&transaction).Where("order_id = ?", payload.OrderID).Update("transaction_status", "settlement")
-		mr.DB.Model(&payment).Where("order_id = ?", payload.OrderID).Updates(map[string]interface{}{
-			"payment_status": "settlement",
-			"payment_date":   payload.TransactionTime,
+			if err := queueTopUpEmail(tx, transaction); err != nil {
+				return err
+			}
+
+			transaction.TransactionStatus = "settlement"
+			return queueWebhookEvent(tx, utils.WebhookTopUpSettled, transaction)
 		})
+
+		if err != nil {
+			log.Println(err)
+		}
 	}
 
-	if payload.TransactionStatus == "expire" || payload.TransactionStatus == "cancel" {
-		var transaction entity.TopUpTransaction
-		var payment entity.Payment
+	if slices.Contains(closedPaymentStatuses, payload.TransactionStatus) {
+		err := mr.DB.Transaction(func(tx *gorm.DB) error {
+			var transaction entity.TopUpTransaction
+
+			payment, err := mr.closePayment(tx, payload)
+			if err != nil || payment == nil {
+				return err
+			}
+
+			// A refused card leaves the order open for another attempt
+			if payload.TransactionStatus == "deny" || payload.TransactionStatus == "failure" {
+				return nil
+			}
+
+			if err := tx.Where("order_id = ?", payment.OrderID).First(&transaction).Error; err != nil {
+				return err
+			}
+
+			if transaction.TransactionStatus != "pending" || mr.hasPendingPayment(tx, transaction.OrderID) {
+				return nil
+			}
+
+			return tx.Model(&transaction).Where("order_id = ?", transaction.OrderID).Update("transaction_status", payload.TransactionStatus).Error
+		})
 
-		mr.DB.Model(&transaction).Where("order_id = ?", payload.OrderID).Update("transaction_status", payload.TransactionStatus)
-		mr.DB.Model(&payment).Where("order_id = ?", payload.OrderID).Update("payment_status", payload.TransactionStatus)
+		if err != nil {
+			log.Println(err)
+		}
 	}
 }
 
 func (mr *midtransRepository) HandleBookingCallback(payload entity.MidtransCallbackResponse) {
+	payload.TransactionStatus = callbackStatus(payload)
+
 	if payload.TransactionStatus == "settlement" {
-		var booking entity.Booking
-		var payment entity.Payment
-		var room entity.Room
-
-		mr.DB.Where("order_id = ?", payload.OrderID).First(&booking)
-		mr.DB.Model(&room).Where("hotel_id = ? AND id = ?", booking.HotelID, booking.RoomID).Update("status", "occupied")
-		mr.DB.Model(&booking).Update("booking_status", "settlement")
-		mr.DB.Model(&payment).Where("order_id = ?", payload.OrderID).Updates(map[string]interface{}{
-			"payment_status": "settlement",
-			"payment_date":   payload.TransactionTime,
+		err := mr.DB.Transaction(func(tx *gorm.DB) error {
+			var booking entity.Booking
+
+			payment, err := mr.findPayment(tx, payload.OrderID)
+			if err != nil {
+				return err
+			}
+
+			// Midtrans repeats notifications, settle only once
+			if payment.PaymentStatus == "settlement" {
+				return nil
+			}
+
+			if err := tx.Model(payment).Updates(map[string]interface{}{
+				"payment_status": "settlement",
+				"payment_date":   payload.TransactionTime,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Rooms").Preload("Charges").Where("order_id = ?", payment.OrderID).First(&booking).Error; err != nil {
+				return err
+			}
+
+			// A split payment also takes the wallet part held for it
+			held, err := settleWalletHold(tx, payment.WalletHoldID)
+			if err != nil {
+				return err
+			}
+
+			// Confirms the booking or pays off its balance, a payment for a paid booking goes to the wallet
+			return applyBookingPayment(tx, &booking, *payment, payment.TotalAmount-payment.Fee+held)
+		})
+
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if slices.Contains(closedPaymentStatuses, payload.TransactionStatus) {
+		err := mr.DB.Transaction(func(tx *gorm.DB) error {
+			var booking entity.Booking
+
+			payment, err := mr.closePayment(tx, payload)
+			if err != nil || payment == nil {
+				return err
+			}
+
+			// A refused card leaves the order open for another attempt
+			if payload.TransactionStatus == "deny" || payload.TransactionStatus == "failure" {
+				return nil
+			}
+
+			if err := tx.Where("order_id = ?", payment.OrderID).First(&booking).Error; err != nil {
+				return err
+			}
+
+			// The guest may still pay through another attempt
+			if booking.BookingStatus != "pending" || mr.hasPendingPayment(tx, booking.OrderID) {
+				return nil
+			}
+
+			if err := tx.Model(&booking).Update("booking_status", payload.TransactionStatus).Error; err != nil {
+				return err
+			}
+
+			// Give the promo usage back so the code can be used again
+			if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ? AND status = ?", booking.OrderID, "held").Update("status", "released").Error; err != nil {
+				return err
+			}
+
+			template, event := utils.EmailBookingCancelled, utils.WebhookBookingCancelled
+			if payload.TransactionStatus == "expire" {
+				template, event = utils.EmailBookingExpired, utils.WebhookBookingExpired
+			}
+
+			if err := queueBookingEmail(tx, booking, template); err != nil {
+				return err
+			}
+
+			booking.BookingStatus = payload.TransactionStatus
+			return queueWebhookEvent(tx, event, booking)
 		})
+
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// findPayment locks the payment attempt Midtrans reports on. Attempts made
+// before per-attempt references were sent under the order's own ID.
+func (mr *midtransRepository) findPayment(tx *gorm.DB, midtransOrderID string) (*entity.Payment, error) {
+	var payment entity.Payment
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("midtrans_order_id = ?", midtransOrderID).
+		Or("midtrans_order_id = '' AND order_id = ?", midtransOrderID).
+		Order("id DESC").First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// closePayment records an expired, cancelled or refused attempt and gives back
+// the wallet part of a split payment. It returns nil when the notification was
+// already handled.
+func (mr *midtransRepository) closePayment(tx *gorm.DB, payload entity.MidtransCallbackResponse) (*entity.Payment, error) {
+	payment, err := mr.findPayment(tx, payload.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentStatus != "pending" {
+		return nil, nil
+	}
+
+	if err := tx.Model(payment).Update("payment_status", payload.TransactionStatus).Error; err != nil {
+		return nil, err
+	}
+
+	if err := releaseWalletHold(tx, payment.WalletHoldID); err != nil {
+		return nil, err
 	}
 
-	if payload.TransactionStatus == "expire" || payload.TransactionStatus == "cancel" {
-		var booking entity.Booking
-		var payment entity.Payment
+	return payment, nil
+}
+
+func (mr *midtransRepository) hasPendingPayment(tx *gorm.DB, orderID string) bool {
+	var count int64
+
+	tx.Model(&entity.Payment{}).Where("order_id = ? AND payment_status = ?", orderID, "pending").Count(&count)
 
-		mr.DB.Model(&booking).Where("order_id = ?", payload.OrderID).Update("booking_status", payload.TransactionStatus)
-		mr.DB.Model(&payment).Where("order_id = ?", payload.OrderID).Update("payment_status", payload.TransactionStatus)
+	return count > 0
+}
+
+// callbackStatus maps a notification onto the statuses handled here. Card
+// payments report "capture" once the charge is approved.
+func callbackStatus(payload entity.MidtransCallbackResponse) string {
+	if payload.TransactionStatus == "capture" && payload.FraudStatus == "accept" {
+		return "settlement"
 	}
+
+	return payload.TransactionStatus
 }
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Deliveries are given up after this many failures
+const MaxNotificationAttempts = 5
+
+type NotificationRepository interface {
+	DeliverPending(limit int, mailer utils.Mailer) (int, error)
+}
+
+type notificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{DB: db}
+}
+
+// DeliverPending sends up to limit due emails and returns how many were sent.
+// Rows are locked while sending so several workers never send the same email.
+func (nr *notificationRepository) DeliverPending(limit int, mailer utils.Mailer) (int, error) {
+	sent := 0
+
+	err := nr.DB.Transaction(func(tx *gorm.DB) error {
+		var notifications []entity.Notification
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", "pending", time.Now()).
+			Order("id").Limit(limit).Find(&notifications)
+
+		if result.Error != nil {
+			return fmt.Errorf("500 | %v", result.Error)
+		}
+
+		for _, notification := range notifications {
+			err := mailer.Send(entity.Email{
+				To:       notification.Recipient,
+				Subject:  notification.Subject,
+				TextBody: notification.TextBody,
+				HTMLBody: notification.HTMLBody,
+			})
+
+			updates := map[string]interface{}{"attempts": notification.Attempts + 1}
+
+			if err == nil {
+				updates["status"] = "sent"
+				updates["sent_at"] = time.Now()
+				updates["last_error"] = ""
+				sent++
+			} else {
+				log.Println(err)
+				updates["last_error"] = err.Error()
+
+				// Back off 1, 4, 9, 16 minutes between attempts
+				if notification.Attempts+1 >= MaxNotificationAttempts {
+					updates["status"] = "failed"
+				} else {
+					backoff := time.Duration((notification.Attempts+1)*(notification.Attempts+1)) * time.Minute
+					updates["next_attempt_at"] = time.Now().Add(backoff)
+				}
+			}
+
+			if err := tx.Model(&notification).Updates(updates).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+		}
+
+		return nil
+	})
+
+	return sent, err
+}
+
+// queueBookingEmail puts an email about the booking in the outbox. Pass the
+// transaction that changes the booking so both are committed together.
+func queueBookingEmail(tx *gorm.DB, booking entity.Booking, template string) error {
+	var hotel entity.Hotel
+	if err := tx.First(&hotel, booking.HotelID).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	data := utils.EmailData{
+		OrderID:     booking.OrderID,
+		BookingCode: booking.BookingCode,
+		HotelName:   hotel.Name,
+		CheckIn:     booking.CheckIn[:10],
+		CheckOut:    booking.CheckOut[:10],
+		Amount:      booking.TotalPrice,
+	}
+
+	// Deposit bookings list what is paid and what is still due
+	if booking.AmountPaid > 0 && booking.AmountPaid < booking.TotalPrice {
+		data.AmountPaid = booking.AmountPaid
+		data.BalanceDue = utils.RoundPrice(booking.TotalPrice - booking.AmountPaid)
+
+		if booking.BalanceDueDate != nil {
+			data.DueDate = booking.BalanceDueDate.Format("2006-01-02")
+		}
+	}
+
+	return queueEmail(tx, booking.GuestID, template, data)
+}
+
+// queueTopUpEmail puts a top-up confirmation in the outbox
+func queueTopUpEmail(tx *gorm.DB, topup entity.TopUpTransaction) error {
+	return queueEmail(tx, topup.UserID, utils.EmailTopUpSettled, utils.EmailData{
+		OrderID: topup.OrderID,
+		Amount:  topup.Amount,
+	})
+}
+
+func queueEmail(tx *gorm.DB, userID uint, template string, data utils.EmailData) error {
+	var user entity.User
+	if err := tx.Where("user_id = ?", userID).First(&user).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	data.Name = user.FirstName
+
+	content, err := utils.RenderEmail(template, user.Locale, data)
+	if err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	notification := entity.Notification{
+		UserID:        user.UserID,
+		Recipient:     user.Email,
+		Template:      template,
+		Locale:        content.Locale,
+		Subject:       content.Subject,
+		TextBody:      content.TextBody,
+		HTMLBody:      content.HTMLBody,
+		Status:        "pending",
+		NextAttemptAt: time.Now(),
+	}
+
+	if err := tx.Create(&notification).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	return nil
+}
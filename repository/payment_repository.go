@@ -2,14 +2,23 @@ package repository
 
 import (
 	"fmt"
+	"log"
 	"lux-hotel/entity"
 	"lux-hotel/utils"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Payment attempts an order can have, to keep a booking from being held forever
+const MaxPaymentAttempts = 5
+
 type PaymentRepository interface {
 	Payment(int, entity.PaymentPayload) (*entity.PaymentResponse, error)
 }
@@ -26,11 +35,32 @@ func (pr *paymentRepository) Payment(userID int, payload entity.PaymentPayload)
 	var response *entity.PaymentResponse
 	var err error
 
+	method, err := utils.FindPaymentMethod(payload.PaymentMethod)
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if method == nil {
+		return nil, fmt.Errorf("400 | Unsupported payment method")
+	}
+
+	if method.Type == "credit_card" && payload.CardToken == "" {
+		return nil, fmt.Errorf("400 | Card token is required for credit card payments")
+	}
+
+	if method.Type == "wallet" && payload.UseWallet {
+		return nil, fmt.Errorf("400 | use_wallet splits a payment between the wallet and another payment method")
+	}
+
 	// Determine transaction type by OrderID prefix
 	if strings.HasPrefix(payload.OrderID, "TPUP") {
-		response, err = pr.handleTopUpPayment(userID, payload)
+		response, err = pr.withOrderLock(payload.OrderID, func() (*entity.PaymentResponse, error) {
+			return pr.handleTopUpPayment(userID, payload, *method)
+		})
 	} else if strings.HasPrefix(payload.OrderID, "BKNG") {
-		response, err = pr.handleBookingPayment(userID, payload)
+		response, err = pr.withOrderLock(payload.OrderID, func() (*entity.PaymentResponse, error) {
+			return pr.handleBookingPayment(userID, payload, *method)
+		})
 	} else {
 		return nil, fmt.Errorf("400 | Invalid OrderID format")
 	}
@@ -42,7 +72,32 @@ func (pr *paymentRepository) Payment(userID int, payload entity.PaymentPayload)
 	return response, nil
 }
 
-func (pr *paymentRepository) handleTopUpPayment(userID int, payload entity.PaymentPayload) (*entity.PaymentResponse, error) {
+// withOrderLock runs one payment request of an order while holding a lock on
+// the order, so two requests cannot both find no reusable attempt and each
+// start a charge. It is an advisory lock rather than a row lock because the
+// steps it covers lock the booking in transactions of their own.
+func (pr *paymentRepository) withOrderLock(orderID string, handle func() (*entity.PaymentResponse, error)) (*entity.PaymentResponse, error) {
+	var response *entity.PaymentResponse
+
+	err := pr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", orderID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		var err error
+		response, err = handle()
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (pr *paymentRepository) handleTopUpPayment(userID int, payload entity.PaymentPayload, method entity.PaymentMethod) (*entity.PaymentResponse, error) {
 	// Fetch the top-up transaction details by order ID
 	topup, topupErr := pr.getTopupTransactionByOrderID(payload.OrderID)
 	if topupErr != nil {
@@ -57,22 +112,22 @@ func (pr *paymentRepository) handleTopUpPayment(userID int, payload entity.Payme
 		return nil, err
 	}
 
-	// Check the transaction status from Midtrans
-	transaction, transactionErr := utils.MidtransTransactionStatusHandler(payload.OrderID)
-	if transactionErr != nil {
-		return nil, fmt.Errorf("500 | %v", transactionErr)
+	if method.Type == "wallet" || payload.UseWallet {
+		return nil, fmt.Errorf("400 | Top-ups cannot be paid from the wallet")
+	}
+
+	if err := pr.validatePaymentAmount(method, topup.Amount); err != nil {
+		return nil, err
+	}
+
+	// Asking again for the same bank returns the pending VA, another method replaces it
+	pending, err := pr.switchPendingPayment(payload, "full")
+	if err != nil {
+		return nil, err
 	}
 
-	// Return pending transaction details
-	if transaction.TransactionStatus == "pending" {
-		return &entity.PaymentResponse{
-			TransactionID:     transaction.TransactionID,
-			TransactionStatus: transaction.TransactionStatus,
-			Amount:            utils.StringToFloat64(transaction.GrossAmount),
-			PaymentType:       transaction.PaymentType,
-			Bank:              transaction.VANumbers[0].Bank,
-			VANumber:          transaction.VANumbers[0].VANumber,
-		}, nil
+	if pending != nil {
+		return pr.pendingPaymentResponse(pending), nil
 	}
 
 	// Fetch the user associated with the top-up
@@ -81,36 +136,18 @@ func (pr *paymentRepository) handleTopUpPayment(userID int, payload entity.Payme
 		return nil, userErr
 	}
 
-	// Prepare the Midtrans payload for the top-up
-	midtransPayload := pr.prepareMidtransPayload(payload, user, topup.Amount, "topup balance")
-
 	// Handle the payment via Midtrans
-	response, responseErr := utils.MidtransPaymentHandler(midtransPayload)
-	if responseErr != nil {
-		return nil, fmt.Errorf("500 | %v", responseErr)
-	}
-
-	transactionID := fmt.Sprintf("TRX-%d", time.Now().Unix())
-	paymentMethod := response.PaymentType + " - " + response.VANumbers[0].Bank
-
-	// Create and save the payment entity
-	payment := pr.createPaymentEntity(transactionID, payload.OrderID, user.UserID, topup.Amount, "topup balance", nil, "pending", paymentMethod)
-	if err := pr.savePayment(payment); err != nil {
-		return nil, err
-	}
-
-	// Return PaymentResponse
-	return &entity.PaymentResponse{
-		TransactionID:     payment.PaymentID,
-		TransactionStatus: payment.PaymentStatus,
-		Amount:            payment.TotalAmount,
-		PaymentType:       payment.TransactionType,
-		Bank:              response.VANumbers[0].Bank,
-		VANumber:          response.VANumbers[0].VANumber,
-	}, nil
+	return pr.chargeMidtrans(payload, method, user, topup.Amount, "topup balance", "full", []entity.MidtransItemDetail{
+		{
+			ID:       payload.OrderID,
+			Price:    utils.MidtransAmount(topup.Amount),
+			Quantity: 1,
+			Name:     "topup balance",
+		},
+	}, nil)
 }
 
-func (pr *paymentRepository) handleBookingPayment(userID int, payload entity.PaymentPayload) (*entity.PaymentResponse, error) {
+func (pr *paymentRepository) handleBookingPayment(userID int, payload entity.PaymentPayload, method entity.PaymentMethod) (*entity.PaymentResponse, error) {
 	// Fetch booking details by order ID
 	booking, bookingErr := pr.getBookingByOrderID(payload.OrderID)
 	if bookingErr != nil {
@@ -121,8 +158,9 @@ func (pr *paymentRepository) handleBookingPayment(userID int, payload entity.Pay
 		return nil, fmt.Errorf("401 | Unauthorized access")
 	}
 
-	// Validate booking status
-	if err := pr.validateBookingStatus(booking); err != nil {
+	// Work out whether this pays the whole booking, its deposit or its balance
+	amount, installment, err := pr.bookingAmountDue(booking, payload)
+	if err != nil {
 		return nil, err
 	}
 
@@ -132,41 +170,115 @@ func (pr *paymentRepository) handleBookingPayment(userID int, payload entity.Pay
 		return nil, err
 	}
 
-	switch payload.PaymentMethod {
-	case "wallet":
-		return pr.handleWalletPayment(payload, booking, user)
-	default:
-		return pr.handleBankPayment(payload, booking, user)
+	// A split payment only charges the method the rest, checked once that is known
+	if method.Type == "wallet" || !payload.UseWallet {
+		if err := pr.validatePaymentAmount(method, amount); err != nil {
+			return nil, err
+		}
 	}
-}
 
-func (pr *paymentRepository) handleWalletPayment(payload entity.PaymentPayload, booking *entity.Booking, user *entity.User) (*entity.PaymentResponse, error) {
-	// Ensure user balance is sufficient
-	if user.Balance < booking.TotalPrice {
+	// Checked before the pending charge is cancelled, so it stays payable
+	if method.Type == "wallet" && user.Balance < amount+utils.PaymentFee(method, amount) {
 		return nil, fmt.Errorf("400 | Insufficient balance")
 	}
 
-	// Deduct balance and save
-	user.Balance -= booking.TotalPrice
-	if err := pr.DB.Save(&user).Error; err != nil {
-		return nil, fmt.Errorf("500 | Failed to update user balance: %v", err)
+	// Switching method cancels the pending charge, the booking stays held meanwhile
+	pending, err := pr.switchPendingPayment(payload, installment)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update booking status to "settlement"
-	booking.BookingStatus = "settlement"
-	if err := pr.DB.Save(&booking).Error; err != nil {
-		return nil, fmt.Errorf("500 | Failed to update booking status: %v", err)
+	if pending != nil {
+		return pr.pendingPaymentResponse(pending), nil
+	}
+
+	switch {
+	case method.Type == "wallet":
+		return pr.handleWalletPayment(payload, method, booking, user, amount, installment)
+	case payload.UseWallet:
+		return pr.handleSplitPayment(payload, method, booking, amount, installment)
+	default:
+		return pr.handleMidtransPayment(payload, method, booking, user, amount, installment)
+	}
+}
+
+// bookingAmountDue returns what a booking payment is for. A pending booking is
+// paid in full or, if its stay was offered one, by deposit. A confirmed
+// booking with a deposit paid can pay off its balance.
+func (pr *paymentRepository) bookingAmountDue(booking *entity.Booking, payload entity.PaymentPayload) (float64, string, error) {
+	switch booking.BookingStatus {
+	case "pending":
+		if !payload.Deposit {
+			return booking.TotalPrice, "full", nil
+		}
+
+		if booking.DepositAmount <= 0 {
+			return 0, "", fmt.Errorf("400 | This booking must be paid in full")
+		}
+
+		return booking.DepositAmount, "deposit", nil
+	case "settlement":
+		balance := utils.RoundPrice(booking.TotalPrice - booking.AmountPaid)
+		if balance <= 0 {
+			return 0, "", fmt.Errorf("400 | Booking has been paid in full")
+		}
+
+		if payload.Deposit {
+			return 0, "", fmt.Errorf("400 | Deposit has already been paid")
+		}
+
+		return balance, "balance", nil
+	default:
+		return 0, "", fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
+	}
+}
+
+func (pr *paymentRepository) handleWalletPayment(payload entity.PaymentPayload, method entity.PaymentMethod, booking *entity.Booking, user *entity.User, amount float64, installment string) (*entity.PaymentResponse, error) {
+	fee := utils.PaymentFee(method, amount)
+
+	// Ensure user balance is sufficient
+	if user.Balance < amount+fee {
+		return nil, fmt.Errorf("400 | Insufficient balance")
 	}
 
-	transactionID := fmt.Sprintf("TRX-%d", time.Now().Unix())
 	paymentDate, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
-	// Create and save payment entity
-	payment := pr.createPaymentEntity(transactionID, payload.OrderID, user.UserID, booking.TotalPrice, "hotel booking", &paymentDate, "settlement", payload.PaymentMethod)
+	// Create payment entity
+	payment := pr.createPaymentEntity(newPaymentID(), payload.OrderID, user.UserID, amount+fee, "hotel booking", &paymentDate, "settlement", payload.PaymentMethod)
 	payment.PaymentStatus = "settlement"
 	payment.PaymentMethod = "wallet"
+	payment.Fee = fee
+	payment.Installment = installment
+
+	// Everything the settlement changes, including the guest's email, commits together
+	err := pr.DB.Transaction(func(tx *gorm.DB) error {
+		// Locked so a concurrent payment of the booking cannot apply twice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Rooms").Preload("Charges").First(booking, booking.ID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
 
-	if err := pr.savePayment(payment); err != nil {
-		return nil, fmt.Errorf("500 | Failed to save payment record: %v", err)
+		due, dueInstallment, err := pr.bookingAmountDue(booking, payload)
+		if err != nil {
+			return err
+		}
+
+		if due != amount || dueInstallment != installment {
+			return fmt.Errorf("409 | Booking changed, please try again")
+		}
+
+		// The guest is locked so transfers and gift codes cannot spend the same balance meanwhile
+		if err := adjustBalance(tx, user.UserID, "booking_payment", -payment.TotalAmount, payment.PaymentID, "Payment for booking "+booking.BookingCode); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&payment).Error; err != nil {
+			return fmt.Errorf("500 | Failed to save payment record: %v", err)
+		}
+
+		return applyBookingPayment(tx, booking, payment, payment.TotalAmount-payment.Fee)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	// Return PaymentResponse
@@ -174,35 +286,449 @@ func (pr *paymentRepository) handleWalletPayment(payload entity.PaymentPayload,
 		TransactionID:     payment.PaymentID,
 		TransactionStatus: payment.PaymentStatus,
 		Amount:            payment.TotalAmount,
+		Fee:               payment.Fee,
 		PaymentType:       payment.TransactionType,
+		Installment:       payment.Installment,
+	}, nil
+}
+
+func (pr *paymentRepository) handleMidtransPayment(payload entity.PaymentPayload, method entity.PaymentMethod, booking *entity.Booking, user *entity.User, amount float64, installment string) (*entity.PaymentResponse, error) {
+	items, err := pr.installmentItemDetails(booking, amount, installment)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.chargeMidtrans(payload, method, user, amount, "hotel booking", installment, items, nil)
+}
+
+// handleSplitPayment pays what the wallet balance covers and charges the rest
+// through Midtrans. The wallet part is held until the Midtrans part settles
+// and given back if that expires, is cancelled or is refused.
+func (pr *paymentRepository) handleSplitPayment(payload entity.PaymentPayload, method entity.PaymentMethod, booking *entity.Booking, amount float64, installment string) (*entity.PaymentResponse, error) {
+	// Read again, cancelling the previous attempt may have given a hold back
+	user, err := pr.getUserByID(booking.GuestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Balance >= amount {
+		return nil, fmt.Errorf("400 | Wallet balance covers the full amount, pay with the wallet instead")
+	}
+
+	// The rest must still be an amount the method accepts
+	walletAmount := math.Floor(min(user.Balance, amount-method.MinAmount))
+	if err := pr.validatePaymentAmount(method, utils.RoundPrice(amount-max(walletAmount, 0))); err != nil {
+		return nil, err
+	}
+
+	if walletAmount <= 0 {
+		return nil, fmt.Errorf("400 | Wallet balance is too low to split the payment")
+	}
+
+	items, err := pr.installmentItemDetails(booking, amount, installment)
+	if err != nil {
+		return nil, err
+	}
+
+	items = append(items, entity.MidtransItemDetail{
+		ID:       "WALLET",
+		Price:    utils.MidtransAmount(-walletAmount),
+		Quantity: 1,
+		Name:     "paid from wallet balance",
+	})
+
+	paymentDate, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	hold := pr.createPaymentEntity(newPaymentID(), payload.OrderID, user.UserID, walletAmount, "hotel booking", &paymentDate, "held", "wallet")
+	hold.Installment = installment
+
+	err = pr.DB.Transaction(func(tx *gorm.DB) error {
+		// The balance may have been spent since it was read
+		err := adjustBalance(tx, user.UserID, "wallet_hold", -walletAmount, hold.PaymentID, "Held for booking "+booking.BookingCode)
+		if err != nil && strings.HasPrefix(err.Error(), "400") {
+			return fmt.Errorf("409 | Wallet balance changed, please try again")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&hold).Error; err != nil {
+			return fmt.Errorf("500 | Failed to save payment record: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := pr.chargeMidtrans(payload, method, user, utils.RoundPrice(amount-walletAmount), "hotel booking", installment, items, &hold)
+	if err != nil {
+		// Nothing was charged, the wallet part goes straight back
+		releaseErr := pr.DB.Transaction(func(tx *gorm.DB) error {
+			return releaseWalletHold(tx, hold.PaymentID)
+		})
+		if releaseErr != nil {
+			log.Printf("Failed to release wallet hold %s: %v", hold.PaymentID, releaseErr)
+		}
+
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// installmentItemDetails lists what a booking payment covers for Midtrans. A
+// deposit or balance covers part of the rooms, so it goes as one line.
+func (pr *paymentRepository) installmentItemDetails(booking *entity.Booking, amount float64, installment string) ([]entity.MidtransItemDetail, error) {
+	if installment == "full" {
+		return pr.bookingItemDetails(booking)
+	}
+
+	return []entity.MidtransItemDetail{
+		{
+			ID:       strings.ToUpper(installment) + "-" + booking.BookingCode,
+			Price:    utils.MidtransAmount(amount),
+			Quantity: 1,
+			Name:     "hotel booking " + installment,
+		},
 	}, nil
 }
 
-func (pr *paymentRepository) handleBankPayment(payload entity.PaymentPayload, booking *entity.Booking, user *entity.User) (*entity.PaymentResponse, error) {
-	midtransPayload := pr.prepareMidtransPayload(payload, user, booking.TotalPrice, "hotel booking")
+// applyBookingPayment books a settled payment against its booking. The first
+// payment, in full or as a deposit, confirms a pending booking; a later one
+// pays off the balance. covered is what the payment pays of the booking,
+// without the method's fee and with any wallet part of a split payment.
+// Whatever the booking no longer needs goes back to the guest's wallet.
+func applyBookingPayment(tx *gorm.DB, booking *entity.Booking, payment entity.Payment, covered float64) error {
+	paid := utils.RoundPrice(booking.AmountPaid + covered)
+
+	if slices.Contains(paidBookingStatuses, booking.BookingStatus) {
+		if booking.AmountPaid >= booking.TotalPrice {
+			log.Printf("Payment %s settled for booking %s which was already paid", payment.PaymentID, booking.OrderID)
+			return creditOverpayment(tx, booking, payment, covered+payment.Fee)
+		}
+
+		if paid > booking.TotalPrice {
+			if err := creditOverpayment(tx, booking, payment, utils.RoundPrice(paid-booking.TotalPrice)); err != nil {
+				return err
+			}
+
+			paid = booking.TotalPrice
+		}
+
+		booking.AmountPaid = paid
+		if err := tx.Model(booking).Update("amount_paid", paid).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return queueBookingEmail(tx, *booking, utils.EmailBookingPaid)
+	}
+
+	// The booking was cancelled for missing the balance deadline
+	if payment.Installment == "balance" {
+		log.Printf("Balance payment %s settled for booking %s which has been %s", payment.PaymentID, booking.OrderID, booking.BookingStatus)
+		return creditOverpayment(tx, booking, payment, covered+payment.Fee)
+	}
+
+	booking.BookingStatus = "settlement"
+	booking.AmountPaid = paid
+
+	err := tx.Model(booking).Updates(map[string]interface{}{
+		"booking_status": booking.BookingStatus,
+		"amount_paid":    booking.AmountPaid,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("500 | Failed to update booking status: %v", err)
+	}
+
+	// Mark the held promo usage as redeemed
+	if err := tx.Model(&entity.PromoRedemption{}).Where("order_id = ? AND status = ?", booking.OrderID, "held").Update("status", "redeemed").Error; err != nil {
+		return fmt.Errorf("500 | Failed to redeem promo code: %v", err)
+	}
+
+	if _, err := issueInvoice(tx, booking); err != nil {
+		return err
+	}
+
+	template := utils.EmailBookingPaid
+	if booking.AmountPaid < booking.TotalPrice {
+		template = utils.EmailDepositPaid
+	}
+
+	if err := queueBookingEmail(tx, *booking, template); err != nil {
+		return err
+	}
+
+	return queueWebhookEvent(tx, utils.WebhookBookingSettled, booking)
+}
+
+// creditOverpayment gives what a settled payment paid beyond the booking's
+// total back to the guest's wallet
+func creditOverpayment(tx *gorm.DB, booking *entity.Booking, payment entity.Payment, amount float64) error {
+	if amount <= 0 {
+		return nil
+	}
+
+	return adjustBalance(tx, booking.GuestID, "booking_refund", amount, payment.PaymentID, "Overpayment for booking "+booking.BookingCode)
+}
+
+// settleWalletHold takes the wallet part of a split payment whose Midtrans
+// part settled and returns its amount
+func settleWalletHold(tx *gorm.DB, holdID string) (float64, error) {
+	if holdID == "" {
+		return 0, nil
+	}
+
+	var hold entity.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("payment_id = ?", holdID).First(&hold).Error; err != nil {
+		return 0, fmt.Errorf("500 | %v", err)
+	}
+
+	// Given back when the attempt was cancelled, the booking is short by this much
+	if hold.PaymentStatus != "held" {
+		log.Printf("Wallet hold %s is %s, its amount was not paid", hold.PaymentID, hold.PaymentStatus)
+		return 0, nil
+	}
+
+	if err := tx.Model(&hold).Update("payment_status", "settlement").Error; err != nil {
+		return 0, fmt.Errorf("500 | %v", err)
+	}
+
+	return hold.TotalAmount, nil
+}
+
+// releaseWalletHold gives the wallet part of a split payment back to the guest
+// once its Midtrans part can no longer be paid
+func releaseWalletHold(tx *gorm.DB, holdID string) error {
+	if holdID == "" {
+		return nil
+	}
+
+	var hold entity.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("payment_id = ?", holdID).First(&hold).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	if hold.PaymentStatus != "held" {
+		return nil
+	}
+
+	if err := tx.Model(&hold).Update("payment_status", "released").Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	return adjustBalance(tx, hold.UserID, "hold_release", hold.TotalAmount, hold.PaymentID, "Released from order "+hold.OrderID)
+}
+
+// chargeMidtrans starts one payment attempt at Midtrans and records it. Every
+// attempt gets its own Midtrans order so the guest can retry with another method.
+// A split payment passes the wallet part already held for it.
+func (pr *paymentRepository) chargeMidtrans(payload entity.PaymentPayload, method entity.PaymentMethod, user *entity.User, amount float64, transactionType string, installment string, items []entity.MidtransItemDetail, hold *entity.Payment) (*entity.PaymentResponse, error) {
+	fee := utils.PaymentFee(method, amount)
+	if fee > 0 {
+		items = append(items, entity.MidtransItemDetail{
+			ID:       "FEE-" + method.Code,
+			Price:    utils.MidtransAmount(fee),
+			Quantity: 1,
+			Name:     method.Name + " fee",
+		})
+	}
+
+	// Bookings priced in sen before amounts were rounded may not add up, they go as one line
+	if itemsTotal(items) != utils.RoundPrice(amount+fee) {
+		items = []entity.MidtransItemDetail{
+			{
+				ID:       strings.ToUpper(installment) + "-" + payload.OrderID,
+				Price:    utils.MidtransAmount(amount + fee),
+				Quantity: 1,
+				Name:     transactionType,
+			},
+		}
+	}
+
+	reference := newMidtransOrderID(payload.OrderID)
+	midtransPayload := pr.prepareMidtransPayload(reference, payload, method, user, amount+fee, items)
+
 	response, err := utils.MidtransPaymentHandler(midtransPayload)
 	if err != nil {
 		return nil, fmt.Errorf("500 | %v", err)
 	}
 
-	paymentMethod := response.PaymentType + " - " + response.VANumbers[0].Bank
+	// 201 is a pending charge, a card charge without 3DS can be captured at once
+	if response.StatusCode != "200" && response.StatusCode != "201" {
+		return nil, fmt.Errorf("400 | Payment was rejected: %s", response.StatusMessage)
+	}
 
-	transactionID := fmt.Sprintf("TRX-%d", time.Now().Unix())
 	// Create and save payment entity
-	payment := pr.createPaymentEntity(transactionID, payload.OrderID, user.UserID, booking.TotalPrice, "hotel booking", nil, "pending", paymentMethod)
+	payment := pr.createPaymentEntity(newPaymentID(), payload.OrderID, user.UserID, amount+fee, transactionType, nil, "pending", response.PaymentType)
+	payment.MidtransOrderID = reference
+	payment.Fee = fee
+	payment.Installment = installment
+
+	if hold != nil {
+		payment.WalletHoldID = hold.PaymentID
+	}
+
+	switch {
+	case len(response.VANumbers) > 0:
+		payment.Bank = response.VANumbers[0].Bank
+		payment.VANumber = response.VANumbers[0].VANumber
+	case response.PermataVANumber != "":
+		payment.Bank = method.Code
+		payment.VANumber = response.PermataVANumber
+	case response.BillKey != "":
+		payment.Bank = method.Code
+		payment.VANumber = response.BillKey
+		payment.BillerCode = response.BillerCode
+	}
+
+	if payment.Bank != "" {
+		payment.PaymentMethod = response.PaymentType + " - " + payment.Bank
+	}
+
 	if err := pr.savePayment(payment); err != nil {
 		return nil, err
 	}
 
-	// Return PaymentResponse
-	return &entity.PaymentResponse{
+	// QR codes, deeplinks and 3DS pages are only shown once, right after the charge
+	paymentResponse := pr.pendingPaymentResponse(&payment)
+	paymentResponse.QRString = response.QRString
+	paymentResponse.RedirectURL = response.RedirectURL
+
+	for _, action := range response.Actions {
+		switch action.Name {
+		case "generate-qr-code":
+			paymentResponse.QRCodeURL = action.URL
+		case "deeplink-redirect":
+			paymentResponse.DeeplinkURL = action.URL
+		}
+	}
+
+	return paymentResponse, nil
+}
+
+// switchPendingPayment goes through the order's pending attempts. One for the
+// same installment made with the requested method that Midtrans still holds
+// open is returned for reuse, every other one is cancelled so only the new
+// attempt can be paid. Attempts are capped per installment.
+func (pr *paymentRepository) switchPendingPayment(payload entity.PaymentPayload, installment string) (*entity.Payment, error) {
+	var attempts int64
+	if err := pr.DB.Model(&entity.Payment{}).Where("order_id = ? AND installment = ? AND payment_method <> ?", payload.OrderID, installment, "wallet").Count(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	var pending []entity.Payment
+	if err := pr.DB.Where("order_id = ? AND payment_status = ?", payload.OrderID, "pending").Order("id DESC").Find(&pending).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	var reusable *entity.Payment
+
+	for i := range pending {
+		sameSplit := (pending[i].WalletHoldID != "") == payload.UseWallet
+		if reusable == nil && pending[i].Installment == installment && sameSplit && strings.EqualFold(pending[i].Bank, payload.PaymentMethod) {
+			transaction, err := utils.MidtransTransactionStatusHandler(midtransOrderID(pending[i]))
+			if err != nil {
+				return nil, fmt.Errorf("500 | %v", err)
+			}
+
+			if transaction.TransactionStatus == "pending" {
+				reusable = &pending[i]
+				continue
+			}
+		}
+
+		if err := pr.cancelPendingPayment(pending[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if reusable == nil && attempts >= MaxPaymentAttempts {
+		return nil, fmt.Errorf("400 | Too many payment attempts for this order")
+	}
+
+	return reusable, nil
+}
+
+// cancelPendingPayment voids one attempt at Midtrans. It is marked cancelled
+// first so the cancel notification Midtrans sends back does not release the
+// booking or top-up. The wallet part of a split attempt is given back.
+func (pr *paymentRepository) cancelPendingPayment(payment entity.Payment) error {
+	result := pr.DB.Model(&payment).Where("payment_status = ?", "pending").Update("payment_status", "cancel")
+	if result.Error != nil {
+		return fmt.Errorf("500 | %v", result.Error)
+	}
+
+	// Closed by a notification in the meantime
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	release := func(tx *gorm.DB) error {
+		return releaseWalletHold(tx, payment.WalletHoldID)
+	}
+
+	response, err := utils.MidtransCancelHandler(midtransOrderID(payment))
+	if err == nil && response.StatusCode == "200" {
+		return pr.DB.Transaction(release)
+	}
+
+	// Midtrans refuses to cancel transactions that are no longer pending
+	transaction, statusErr := utils.MidtransTransactionStatusHandler(midtransOrderID(payment))
+	if statusErr == nil && slices.Contains(closedPaymentStatuses, transaction.TransactionStatus) {
+		pr.DB.Model(&payment).Update("payment_status", transaction.TransactionStatus)
+		return pr.DB.Transaction(release)
+	}
+
+	// Possibly paid, the notification settles it
+	pr.DB.Model(&payment).Update("payment_status", "pending")
+
+	return fmt.Errorf("409 | The pending payment could not be cancelled, it may already have been paid")
+}
+
+func (pr *paymentRepository) pendingPaymentResponse(payment *entity.Payment) *entity.PaymentResponse {
+	response := &entity.PaymentResponse{
 		TransactionID:     payment.PaymentID,
 		TransactionStatus: payment.PaymentStatus,
 		Amount:            payment.TotalAmount,
+		Fee:               payment.Fee,
 		PaymentType:       payment.TransactionType,
-		Bank:              response.VANumbers[0].Bank,
-		VANumber:          response.VANumbers[0].VANumber,
-	}, nil
+		Installment:       payment.Installment,
+		Bank:              payment.Bank,
+		VANumber:          payment.VANumber,
+		MidtransOrderID:   payment.MidtransOrderID,
+	}
+
+	if payment.WalletHoldID != "" {
+		var hold entity.Payment
+		if err := pr.DB.Where("payment_id = ?", payment.WalletHoldID).First(&hold).Error; err == nil {
+			response.WalletAmount = hold.TotalAmount
+		}
+	}
+
+	// Mandiri bills are paid with a biller code and bill key instead of a VA
+	if payment.BillerCode != "" {
+		response.BillerCode = payment.BillerCode
+		response.BillKey = payment.VANumber
+		response.VANumber = ""
+	}
+
+	return response
+}
+
+// validatePaymentAmount checks the amount due against the method's limits
+func (pr *paymentRepository) validatePaymentAmount(method entity.PaymentMethod, amount float64) error {
+	if amount < method.MinAmount {
+		return fmt.Errorf("400 | %s requires at least %.2f", method.Name, method.MinAmount)
+	}
+
+	if method.MaxAmount > 0 && amount > method.MaxAmount {
+		return fmt.Errorf("400 | %s accepts at most %.2f", method.Name, method.MaxAmount)
+	}
+
+	return nil
 }
 
 func (pr *paymentRepository) getBookingByOrderID(orderID string) (*entity.Booking, error) {
@@ -229,14 +755,6 @@ func (pr *paymentRepository) validateTopupTransactionStatus(topup *entity.TopUpT
 	return nil
 }
 
-func (pr *paymentRepository) validateBookingStatus(booking *entity.Booking) error {
-	if booking.BookingStatus != "pending" {
-		return fmt.Errorf("400 | Booking has been %s", booking.BookingStatus)
-	}
-
-	return nil
-}
-
 func (pr *paymentRepository) getTopupTransactionByOrderID(orderID string) (*entity.TopUpTransaction, error) {
 	var topup entity.TopUpTransaction
 
@@ -269,15 +787,100 @@ func (pr *paymentRepository) getUserByID(userID uint) (*entity.User, error) {
 	return &user, nil
 }
 
-func (pr *paymentRepository) prepareMidtransPayload(payload entity.PaymentPayload, user *entity.User, amount float64, txName string) entity.MidtransPaymentPayload {
-	return entity.MidtransPaymentPayload{
-		PaymentType: "bank_transfer",
+// itemsTotal adds up item details the way Midtrans checks them against the gross amount
+func itemsTotal(items []entity.MidtransItemDetail) float64 {
+	total := 0.0
+
+	for _, item := range items {
+		price, _ := strconv.ParseFloat(item.Price, 64)
+		total += price * float64(item.Quantity)
+	}
+
+	return total
+}
+
+// bookingItemDetails lists each room and each exclusive tax or fee as separate
+// Midtrans items; inclusive charges are already part of the room lines.
+func (pr *paymentRepository) bookingItemDetails(booking *entity.Booking) ([]entity.MidtransItemDetail, error) {
+	var charges []entity.BookingCharge
+
+	if result := pr.DB.Where("booking_id = ?", booking.ID).Order("id").Find(&charges); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	var rooms []entity.BookingRoom
+
+	if result := pr.DB.Where("booking_id = ? AND status = ?", booking.ID, "active").Order("id").Find(&rooms); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	items := make([]entity.MidtransItemDetail, 0, len(rooms)+len(charges)+1)
+
+	for _, room := range rooms {
+		item := entity.MidtransItemDetail{
+			ID:       fmt.Sprintf("ROOM-%d", room.RoomID),
+			Price:    utils.MidtransAmount(room.SubTotal),
+			Quantity: 1,
+			Name:     fmt.Sprintf("room %s - %d night(s)", room.RoomNumber, booking.TotalDays),
+		}
+
+		// Room type bookings have no room assigned yet
+		if room.RoomID == 0 && room.RoomTypeID != nil {
+			item.ID = fmt.Sprintf("TYPE-%d", *room.RoomTypeID)
+			item.Name = fmt.Sprintf("room - %d night(s)", booking.TotalDays)
+		}
+
+		items = append(items, item)
+	}
+
+	// Bookings made before multi-room orders have a single room and no lines
+	if len(rooms) == 0 {
+		subTotal := booking.SubTotal
+		if subTotal == 0 {
+			subTotal = booking.TotalPrice
+		}
+
+		items = append(items, entity.MidtransItemDetail{
+			ID:       fmt.Sprintf("ROOM-%d", booking.RoomID),
+			Price:    utils.MidtransAmount(subTotal),
+			Quantity: 1,
+			Name:     fmt.Sprintf("hotel booking - %d night(s)", booking.TotalDays),
+		})
+	}
+
+	if booking.Discount > 0 {
+		items = append(items, entity.MidtransItemDetail{
+			ID:       "PROMO-" + booking.PromoCode,
+			Price:    utils.MidtransAmount(-booking.Discount),
+			Quantity: 1,
+			Name:     "promo " + booking.PromoCode,
+		})
+	}
+
+	for _, charge := range charges {
+		if charge.Inclusive {
+			continue
+		}
+
+		items = append(items, entity.MidtransItemDetail{
+			ID:       fmt.Sprintf("CHG-%d", charge.HotelChargeID),
+			Price:    utils.MidtransAmount(charge.Amount),
+			Quantity: 1,
+			Name:     charge.Name,
+		})
+	}
+
+	return items, nil
+}
+
+func (pr *paymentRepository) prepareMidtransPayload(midtransOrderID string, payload entity.PaymentPayload, method entity.PaymentMethod, user *entity.User, amount float64, items []entity.MidtransItemDetail) entity.MidtransPaymentPayload {
+	midtransPayload := entity.MidtransPaymentPayload{
 		TransactionDetail: struct {
 			OrderID     string `json:"order_id"`
 			GrossAmount string `json:"gross_amount"`
 		}{
-			OrderID:     payload.OrderID,
-			GrossAmount: fmt.Sprintf("%.2f", amount),
+			OrderID:     midtransOrderID,
+			GrossAmount: utils.MidtransAmount(amount),
 		},
 		CustomerDetail: struct {
 			Email     string `json:"email"`
@@ -290,25 +893,32 @@ func (pr *paymentRepository) prepareMidtransPayload(payload entity.PaymentPayloa
 			LastName:  user.LastName,
 			Phone:     user.PhoneNumber,
 		},
-		ItemDetails: []struct {
-			ID       string `json:"id"`
-			Price    string `json:"price"`
-			Quantity int    `json:"quantity"`
-			Name     string `json:"name"`
-		}{
-			{
-				ID:       payload.OrderID,
-				Price:    fmt.Sprintf("%.2f", amount),
-				Quantity: 1,
-				Name:     txName,
-			},
-		},
-		BankTransfer: struct {
-			Bank string `json:"bank"`
-		}{
-			Bank: payload.PaymentMethod,
-		},
+		ItemDetails: items,
+	}
+
+	switch method.Type {
+	case "echannel":
+		midtransPayload.PaymentType = "echannel"
+		midtransPayload.Echannel = &entity.MidtransEchannel{
+			BillInfo1: "Payment:",
+			BillInfo2: "Lux Hotel",
+		}
+	case "qris", "gopay":
+		midtransPayload.PaymentType = method.Type
+	case "credit_card":
+		midtransPayload.PaymentType = "credit_card"
+		midtransPayload.CreditCard = &entity.MidtransCreditCard{
+			TokenID:        payload.CardToken,
+			Authentication: true,
+		}
+	default:
+		midtransPayload.PaymentType = "bank_transfer"
+		midtransPayload.BankTransfer = &entity.MidtransBankTransfer{
+			Bank: method.Code,
+		}
 	}
+
+	return midtransPayload
 }
 
 func (pr *paymentRepository) createPaymentEntity(transactionID string, orderID string, userID uint, amount float64, transactionType string, paymentDate *time.Time, paymentStatus string, paymentMethod string) entity.Payment {
@@ -333,3 +943,25 @@ func (pr *paymentRepository) savePayment(payment entity.Payment) error {
 
 	return nil
 }
+
+func newPaymentID() string {
+	return "TRX-" + uuid.New().String()
+}
+
+// newMidtransOrderID returns a fresh Midtrans order_id for one payment attempt.
+// It keeps the BKNG or TPUP prefix the callback routes on.
+func newMidtransOrderID(orderID string) string {
+	prefix, _, _ := strings.Cut(orderID, "-")
+
+	return prefix + "-" + strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+// midtransOrderID returns the order_id Midtrans knows a payment attempt by.
+// Attempts made before per-attempt references were sent under the order's ID.
+func midtransOrderID(payment entity.Payment) string {
+	if payment.MidtransOrderID == "" {
+		return payment.OrderID
+	}
+
+	return payment.MidtransOrderID
+}
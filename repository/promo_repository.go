@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type PromoRepository interface {
+	GetPromoCodes() ([]entity.PromoCode, error)
+	CreatePromoCode(entity.PromoCodePayload) (*entity.PromoCode, error)
+	UpdatePromoCode(int, entity.PromoCodePayload) (*entity.PromoCode, error)
+	GetPromoRedemptions(int) ([]entity.PromoRedemption, error)
+}
+
+type promoRepository struct {
+	DB *gorm.DB
+}
+
+func NewPromoRepository(db *gorm.DB) PromoRepository {
+	return &promoRepository{DB: db}
+}
+
+func (pr *promoRepository) GetPromoCodes() ([]entity.PromoCode, error) {
+	var promos []entity.PromoCode
+
+	result := pr.DB.Preload("Hotels").Order("id DESC").Find(&promos)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return promos, nil
+}
+
+func (pr *promoRepository) CreatePromoCode(payload entity.PromoCodePayload) (*entity.PromoCode, error) {
+	code := strings.ToUpper(strings.TrimSpace(payload.Code))
+
+	if codeExists := pr.DB.Where("code = ?", code).First(&entity.PromoCode{}); codeExists.RowsAffected > 0 {
+		return nil, fmt.Errorf("409 | Promo code already exists")
+	}
+
+	hotels, err := pr.getHotelsByID(payload.HotelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	promo := entity.PromoCode{Code: code, Active: true}
+	pr.applyPromoPayload(&promo, payload)
+	promo.Hotels = hotels
+
+	if result := pr.DB.Create(&promo); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &promo, nil
+}
+
+func (pr *promoRepository) UpdatePromoCode(promoID int, payload entity.PromoCodePayload) (*entity.PromoCode, error) {
+	promo, err := pr.getPromoByID(promoID)
+	if err != nil {
+		return nil, err
+	}
+
+	hotels, err := pr.getHotelsByID(payload.HotelIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The code itself is kept so existing redemptions stay traceable
+	pr.applyPromoPayload(promo, payload)
+
+	err = pr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Hotels").Save(promo).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(promo).Association("Hotels").Replace(hotels)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	promo.Hotels = hotels
+
+	return promo, nil
+}
+
+func (pr *promoRepository) GetPromoRedemptions(promoID int) ([]entity.PromoRedemption, error) {
+	var redemptions []entity.PromoRedemption
+
+	if _, err := pr.getPromoByID(promoID); err != nil {
+		return nil, err
+	}
+
+	result := pr.DB.Where("promo_code_id = ?", promoID).Order("created_at DESC").Find(&redemptions)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return redemptions, nil
+}
+
+func (pr *promoRepository) getPromoByID(promoID int) (*entity.PromoCode, error) {
+	var promo entity.PromoCode
+
+	result := pr.DB.First(&promo, promoID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Promo code not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &promo, nil
+}
+
+func (pr *promoRepository) getHotelsByID(hotelIDs []uint) ([]entity.Hotel, error) {
+	var hotels []entity.Hotel
+
+	if len(hotelIDs) == 0 {
+		return hotels, nil
+	}
+
+	if result := pr.DB.Where("id IN ?", hotelIDs).Find(&hotels); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if len(hotels) != len(hotelIDs) {
+		return nil, fmt.Errorf("404 | Hotel not found")
+	}
+
+	return hotels, nil
+}
+
+func (pr *promoRepository) applyPromoPayload(promo *entity.PromoCode, payload entity.PromoCodePayload) {
+	promo.Description = payload.Description
+	promo.DiscountType = payload.DiscountType
+	promo.DiscountValue = payload.DiscountValue
+	promo.MaxDiscount = payload.MaxDiscount
+	promo.ValidFrom = payload.ValidFrom
+	promo.ValidUntil = payload.ValidUntil
+	promo.UsageLimit = payload.UsageLimit
+	promo.PerUserLimit = payload.PerUserLimit
+	promo.MinNights = payload.MinNights
+
+	if payload.Active != nil {
+		promo.Active = *payload.Active
+	}
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pending payments younger than this are left to the Midtrans callback
+const reconcileStaleAfter = 15 * time.Minute
+
+// Status codes of a status lookup that describe a transaction: settled or
+// captured, pending, and expired
+var transactionStatusCodes = []string{"200", "201", "407"}
+
+type ReconciliationRepository interface {
+	Reconcile(limit int) (*entity.ReconciliationReport, error)
+	GetDiscrepancies(resolved *bool) ([]entity.PaymentDiscrepancy, error)
+	ResolveDiscrepancy(discrepancyID int) (*entity.PaymentDiscrepancy, error)
+}
+
+type reconciliationRepository struct {
+	DB       *gorm.DB
+	Midtrans *midtransRepository
+}
+
+func NewReconciliationRepository(db *gorm.DB) ReconciliationRepository {
+	return &reconciliationRepository{DB: db, Midtrans: &midtransRepository{DB: db}}
+}
+
+// Reconcile looks up stale pending payments at Midtrans and applies what a lost
+// callback would have, least recently checked first. Findings that need a
+// person are stored as discrepancies.
+func (rr *reconciliationRepository) Reconcile(limit int) (*entity.ReconciliationReport, error) {
+	report := entity.ReconciliationReport{
+		StartedAt:     time.Now(),
+		Discrepancies: []entity.PaymentDiscrepancy{},
+	}
+
+	var payments []entity.Payment
+
+	result := rr.DB.Where("payment_status = ? AND payment_method <> ? AND created_at < ?", "pending", "wallet", time.Now().Add(-reconcileStaleAfter)).
+		Order("reconciled_at NULLS FIRST, id").Limit(limit).Find(&payments)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	for _, payment := range payments {
+		// Not touching updated_at, which dates the payment's own changes
+		if err := rr.DB.Model(&payment).UpdateColumn("reconciled_at", time.Now()).Error; err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+
+		report.Checked++
+
+		reference := midtransOrderID(payment)
+
+		transaction, err := utils.MidtransTransactionStatusHandler(reference)
+		if err != nil {
+			log.Printf("Reconciliation of payment %s failed: %v", payment.PaymentID, err)
+			continue
+		}
+
+		if transaction.StatusCode == "404" {
+			rr.recordDiscrepancy(&report, payment, transaction, "not_found_at_gateway", "Midtrans has no transaction for this payment", false)
+			continue
+		}
+
+		// Other answers, e.g. a wrong server key or a Midtrans outage, carry no transaction to compare
+		if !slices.Contains(transactionStatusCodes, transaction.StatusCode) {
+			log.Printf("Reconciliation of payment %s skipped, Midtrans answered %s: %s", payment.PaymentID, transaction.StatusCode, transaction.StatusMessage)
+			continue
+		}
+
+		// Settling a different amount than was charged needs a person to decide
+		if math.Abs(utils.StringToFloat64(transaction.GrossAmount)-utils.RoundPrice(payment.TotalAmount)) >= 0.01 {
+			rr.recordDiscrepancy(&report, payment, transaction, "amount_mismatch", "Midtrans amount differs from the payment, its status was left unchanged", false)
+			continue
+		}
+
+		callback := entity.MidtransCallbackResponse{
+			OrderID:           reference,
+			TransactionID:     transaction.TransactionID,
+			TransactionStatus: transaction.TransactionStatus,
+			TransactionTime:   transaction.TransactionTime,
+			FraudStatus:       transaction.FraudStatus,
+			PaymentType:       transaction.PaymentType,
+			GrossAmount:       transaction.GrossAmount,
+			StatusCode:        transaction.StatusCode,
+		}
+
+		status := callbackStatus(callback)
+		if status != "settlement" && !slices.Contains(closedPaymentStatuses, status) {
+			continue
+		}
+
+		// The same transitions as the callback
+		if strings.HasPrefix(reference, "TPUP") {
+			rr.Midtrans.HandleTopUpCallback(callback)
+		} else {
+			rr.Midtrans.HandleBookingCallback(callback)
+		}
+
+		pending := payment
+		if err := rr.DB.First(&payment, payment.ID).Error; err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+
+		// The handler logs why it could not apply the status
+		if payment.PaymentStatus == "pending" {
+			continue
+		}
+
+		report.Updated++
+
+		if status != "settlement" {
+			continue
+		}
+
+		rr.recordDiscrepancy(&report, pending, transaction, "settled_at_gateway", "Callback was missed, the payment was settled by reconciliation", true)
+
+		// A deposit and its balance are separate installments, the wallet part of a split payment is not a payment of its own
+		var settled int64
+		err = rr.DB.Model(&entity.Payment{}).
+			Where("order_id = ? AND installment = ? AND payment_status = ?", payment.OrderID, payment.Installment, "settlement").
+			Where("payment_id NOT IN (?)", rr.DB.Model(&entity.Payment{}).Select("wallet_hold_id").Where("order_id = ? AND wallet_hold_id <> ''", payment.OrderID)).
+			Count(&settled).Error
+		if err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+
+		if settled > 1 {
+			rr.recordDiscrepancy(&report, payment, transaction, "duplicate_payment", fmt.Sprintf("Order has %d settled %s payments", settled, payment.Installment), false)
+		}
+	}
+
+	return &report, nil
+}
+
+func (rr *reconciliationRepository) GetDiscrepancies(resolved *bool) ([]entity.PaymentDiscrepancy, error) {
+	var discrepancies []entity.PaymentDiscrepancy
+
+	query := rr.DB.Order("id DESC")
+	if resolved != nil {
+		query = query.Where("resolved = ?", *resolved)
+	}
+
+	if result := query.Find(&discrepancies); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return discrepancies, nil
+}
+
+func (rr *reconciliationRepository) ResolveDiscrepancy(discrepancyID int) (*entity.PaymentDiscrepancy, error) {
+	var discrepancy entity.PaymentDiscrepancy
+
+	result := rr.DB.First(&discrepancy, discrepancyID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Discrepancy not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	discrepancy.Resolved = true
+
+	if err := rr.DB.Model(&discrepancy).Update("resolved", true).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	return &discrepancy, nil
+}
+
+// recordDiscrepancy stores a finding once, an open discrepancy of the same
+// kind for the payment is not repeated on later runs
+func (rr *reconciliationRepository) recordDiscrepancy(report *entity.ReconciliationReport, payment entity.Payment, transaction *entity.MidtransResponse, kind string, detail string, resolved bool) {
+	if result := rr.DB.Where("payment_id = ? AND kind = ? AND resolved = ?", payment.PaymentID, kind, false).First(&entity.PaymentDiscrepancy{}); result.RowsAffected > 0 {
+		return
+	}
+
+	discrepancy := entity.PaymentDiscrepancy{
+		PaymentID:       payment.PaymentID,
+		OrderID:         payment.OrderID,
+		MidtransOrderID: midtransOrderID(payment),
+		Kind:            kind,
+		LocalStatus:     payment.PaymentStatus,
+		GatewayStatus:   transaction.TransactionStatus,
+		LocalAmount:     payment.TotalAmount,
+		GatewayAmount:   utils.StringToFloat64(transaction.GrossAmount),
+		Detail:          detail,
+		Resolved:        resolved,
+	}
+
+	if err := rr.DB.Create(&discrepancy).Error; err != nil {
+		log.Printf("Failed to record %s for payment %s: %v", kind, payment.PaymentID, err)
+		return
+	}
+
+	report.Discrepancies = append(report.Discrepancies, discrepancy)
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReviewRepository interface {
+	CreateReview(userID int, orderID string, payload entity.ReviewPayload) (*entity.Review, error)
+	GetHotelReviews(hotelID int) (*entity.HotelReviewsResponse, error)
+	GetReviews(status string) ([]entity.Review, error)
+	ModerateReview(reviewID int, status string) (*entity.Review, error)
+	ReplyReview(reviewID int, reply string) (*entity.Review, error)
+}
+
+type reviewRepository struct {
+	DB *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{DB: db}
+}
+
+func (rr *reviewRepository) CreateReview(userID int, orderID string, payload entity.ReviewPayload) (*entity.Review, error) {
+	var booking entity.Booking
+
+	result := rr.DB.Where("order_id = ?", orderID).First(&booking)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Booking not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if booking.GuestID != uint(userID) {
+		return nil, fmt.Errorf("401 | Unauthorized access")
+	}
+
+	if err := rr.validateCompletedStay(&booking); err != nil {
+		return nil, err
+	}
+
+	if reviewExists := rr.DB.Where("booking_id = ?", booking.ID).First(&entity.Review{}); reviewExists.RowsAffected > 0 {
+		return nil, fmt.Errorf("409 | Booking has already been reviewed")
+	}
+
+	var user entity.User
+	if err := rr.DB.Where("user_id = ?", userID).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("404 | User not found")
+	}
+
+	review := entity.Review{
+		BookingID:         booking.ID,
+		OrderID:           booking.OrderID,
+		HotelID:           booking.HotelID,
+		UserID:            user.UserID,
+		GuestName:         strings.TrimSpace(user.FirstName + " " + user.LastName),
+		OverallRating:     payload.OverallRating,
+		CleanlinessRating: payload.CleanlinessRating,
+		ServiceRating:     payload.ServiceRating,
+		LocationRating:    payload.LocationRating,
+		ValueRating:       payload.ValueRating,
+		Title:             payload.Title,
+		Comment:           payload.Comment,
+		Status:            "pending",
+	}
+
+	if result := rr.DB.Create(&review); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &review, nil
+}
+
+func (rr *reviewRepository) GetHotelReviews(hotelID int) (*entity.HotelReviewsResponse, error) {
+	var hotel entity.Hotel
+	var response entity.HotelReviewsResponse
+
+	if result := rr.DB.First(&hotel, hotelID); result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Hotel not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	// Category averages skip reviews that left the category unrated
+	result := rr.DB.Model(&entity.Review{}).
+		Select(`COUNT(*) AS review_count,
+			COALESCE(AVG(overall_rating), 0) AS overall_rating,
+			COALESCE(AVG(NULLIF(cleanliness_rating, 0)), 0) AS cleanliness_rating,
+			COALESCE(AVG(NULLIF(service_rating, 0)), 0) AS service_rating,
+			COALESCE(AVG(NULLIF(location_rating, 0)), 0) AS location_rating,
+			COALESCE(AVG(NULLIF(value_rating, 0)), 0) AS value_rating`).
+		Where("hotel_id = ? AND status = ?", hotel.ID, "approved").
+		Scan(&response.Summary)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	result = rr.DB.Where("hotel_id = ? AND status = ?", hotel.ID, "approved").Order("created_at DESC").Find(&response.Reviews)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &response, nil
+}
+
+func (rr *reviewRepository) GetReviews(status string) ([]entity.Review, error) {
+	var reviews []entity.Review
+
+	query := rr.DB.Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if result := query.Find(&reviews); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return reviews, nil
+}
+
+// ModerateReview approves or rejects a review and refreshes the hotel's rating
+func (rr *reviewRepository) ModerateReview(reviewID int, status string) (*entity.Review, error) {
+	review, err := rr.getReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = status
+
+	err = rr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(review).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE hotels SET
+			average_rating = COALESCE((SELECT AVG(overall_rating) FROM reviews WHERE hotel_id = hotels.id AND status = 'approved'), 0),
+			review_count = (SELECT COUNT(*) FROM reviews WHERE hotel_id = hotels.id AND status = 'approved')
+			WHERE id = ?`, review.HotelID).Error
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	return review, nil
+}
+
+func (rr *reviewRepository) ReplyReview(reviewID int, reply string) (*entity.Review, error) {
+	review, err := rr.getReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.HotelReply = reply
+	review.RepliedAt = &now
+
+	if result := rr.DB.Save(review); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return review, nil
+}
+
+// validateCompletedStay only lets guests review once a paid stay has ended
+func (rr *reviewRepository) validateCompletedStay(booking *entity.Booking) error {
+	if booking.BookingStatus == "checked_out" {
+		return nil
+	}
+
+	// Hotels that do not use front-desk check-out leave stays at settlement
+	if booking.BookingStatus != "settlement" {
+		return fmt.Errorf("400 | Only completed stays can be reviewed")
+	}
+
+	checkOut, _ := time.Parse("2006-01-02", booking.CheckOut[:10])
+	if time.Now().Before(checkOut) {
+		return fmt.Errorf("400 | Stay can be reviewed after check-out")
+	}
+
+	return nil
+}
+
+func (rr *reviewRepository) getReviewByID(reviewID int) (*entity.Review, error) {
+	var review entity.Review
+
+	result := rr.DB.First(&review, reviewID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Review not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &review, nil
+}
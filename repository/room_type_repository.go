@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoomTypeRepository interface {
+	CreateRoomType(hotelID int, payload entity.RoomTypePayload) (*entity.RoomType, error)
+	UpdateRoomType(roomTypeID int, payload entity.RoomTypePayload) (*entity.RoomType, error)
+	DeleteRoomType(roomTypeID int) error
+	SetRoomType(roomID int, payload entity.RoomTypeAssignPayload) (*entity.Room, error)
+}
+
+type roomTypeRepository struct {
+	DB *gorm.DB
+}
+
+func NewRoomTypeRepository(db *gorm.DB) RoomTypeRepository {
+	return &roomTypeRepository{DB: db}
+}
+
+func (rr *roomTypeRepository) CreateRoomType(hotelID int, payload entity.RoomTypePayload) (*entity.RoomType, error) {
+	var hotel entity.Hotel
+
+	if result := rr.DB.First(&hotel, hotelID); result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Hotel not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	roomType := entity.RoomType{HotelID: hotel.ID}
+	rr.applyRoomTypePayload(&roomType, payload)
+
+	if result := rr.DB.Create(&roomType); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &roomType, nil
+}
+
+func (rr *roomTypeRepository) UpdateRoomType(roomTypeID int, payload entity.RoomTypePayload) (*entity.RoomType, error) {
+	roomType, err := rr.getRoomTypeByID(roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rr.applyRoomTypePayload(roomType, payload)
+
+	if result := rr.DB.Save(roomType); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return roomType, nil
+}
+
+func (rr *roomTypeRepository) DeleteRoomType(roomTypeID int) error {
+	roomType, err := rr.getRoomTypeByID(roomTypeID)
+	if err != nil {
+		return err
+	}
+
+	if linked := rr.DB.Where("room_type_id = ?", roomType.ID).First(&entity.Room{}); linked.RowsAffected > 0 {
+		return fmt.Errorf("409 | Room type still has rooms")
+	}
+
+	return rr.DB.Transaction(func(tx *gorm.DB) error {
+		// Locked so no booking of the type comes in meanwhile
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(roomType, roomType.ID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		held, err := rr.peakTypeLines(tx, roomType.ID)
+		if err != nil {
+			return err
+		}
+
+		if held > 0 {
+			return fmt.Errorf("400 | Room type has upcoming bookings")
+		}
+
+		if err := tx.Delete(roomType).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+}
+
+func (rr *roomTypeRepository) SetRoomType(roomID int, payload entity.RoomTypeAssignPayload) (*entity.Room, error) {
+	var room entity.Room
+
+	if result := rr.DB.First(&room, roomID); result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Room not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if payload.RoomTypeID != nil {
+		roomType, err := rr.getRoomTypeByID(int(*payload.RoomTypeID))
+		if err != nil {
+			return nil, err
+		}
+
+		if roomType.HotelID != room.HotelID {
+			return nil, fmt.Errorf("400 | Room type belongs to another hotel")
+		}
+
+		room.Type = roomType
+	}
+
+	err := rr.DB.Transaction(func(tx *gorm.DB) error {
+		// Rooms before types, in the order bookings lock them
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, room.ID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		// The type the room leaves must keep a room for every booking it holds
+		if room.RoomTypeID != nil && (payload.RoomTypeID == nil || *payload.RoomTypeID != *room.RoomTypeID) {
+			if err := rr.checkTypeCapacity(tx, *room.RoomTypeID, room.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&room).Update("room_type_id", payload.RoomTypeID).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	room.RoomTypeID = payload.RoomTypeID
+
+	return &room, nil
+}
+
+// checkTypeCapacity rejects taking a room out of a room type when the rooms
+// left could not take every booking of the type on its busiest night
+func (rr *roomTypeRepository) checkTypeCapacity(tx *gorm.DB, roomTypeID uint, roomID uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entity.RoomType{}, roomTypeID).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	var rooms int64
+	if err := tx.Model(&entity.Room{}).Where("room_type_id = ? AND id <> ?", roomTypeID, roomID).Count(&rooms).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	held, err := rr.peakTypeLines(tx, roomTypeID)
+	if err != nil {
+		return err
+	}
+
+	if int64(held) > rooms {
+		return fmt.Errorf("400 | Room type has %d upcoming bookings on the same night and would only have %d rooms left", held, rooms)
+	}
+
+	return nil
+}
+
+// peakTypeLines returns the most lines of a room type, assigned a room or not,
+// that hold a room on any one night from today on
+func (rr *roomTypeRepository) peakTypeLines(tx *gorm.DB, roomTypeID uint) (int, error) {
+	var stays []struct {
+		CheckIn  time.Time
+		CheckOut time.Time
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	err := tx.Table("booking_rooms").
+		Select("bookings.check_in, bookings.check_out").
+		Joins("JOIN bookings ON bookings.id = booking_rooms.booking_id").
+		Where("booking_rooms.room_type_id = ? AND booking_rooms.status = ?", roomTypeID, "active").
+		Where("bookings.booking_status IN ?", roomHoldingStatuses).
+		Where("bookings.check_out > ?", today).
+		Scan(&stays).Error
+	if err != nil {
+		return 0, fmt.Errorf("500 | %v", err)
+	}
+
+	nights := map[string]int{}
+	peak := 0
+
+	for _, stay := range stays {
+		for night := stay.CheckIn; night.Before(stay.CheckOut); night = night.AddDate(0, 0, 1) {
+			if key := night.Format("2006-01-02"); key >= today {
+				nights[key]++
+				peak = max(peak, nights[key])
+			}
+		}
+	}
+
+	return peak, nil
+}
+
+func (rr *roomTypeRepository) getRoomTypeByID(roomTypeID int) (*entity.RoomType, error) {
+	var roomType entity.RoomType
+
+	result := rr.DB.First(&roomType, roomTypeID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Room type not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &roomType, nil
+}
+
+func (rr *roomTypeRepository) applyRoomTypePayload(roomType *entity.RoomType, payload entity.RoomTypePayload) {
+	roomType.Name = payload.Name
+	roomType.Description = payload.Description
+	roomType.MaxAdults = payload.MaxAdults
+	roomType.MaxChildren = payload.MaxChildren
+	roomType.BedType = payload.BedType
+	roomType.BedCount = payload.BedCount
+	roomType.SizeSqm = payload.SizeSqm
+	roomType.Amenities = payload.Amenities
+	roomType.Photos = payload.Photos
+	roomType.BaseRate = payload.BaseRate
+
+	if roomType.BedCount == 0 {
+		roomType.BedCount = 1
+	}
+}
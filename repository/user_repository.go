@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log"
 	"lux-hotel/entity"
+	"lux-hotel/utils"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -17,6 +19,9 @@ type UserRepository interface {
 	TopUpBalance(int, entity.UserTopUpBalancePayload) (*entity.TopUpTransaction, error)
 	GetBookHistory(int) ([]entity.BookingHistoryResponse, error)
 	GetUserByEmail(string) (*entity.User, error)
+	AddFavorite(int, int) error
+	RemoveFavorite(int, int) error
+	GetFavorites(int) ([]entity.GetHotelList, error)
 }
 
 type userRepository struct {
@@ -51,6 +56,12 @@ func (ur *userRepository) Register(request entity.UserRegisterPayload) (*entity.
 		Email:       request.Email,
 		Password:    request.Password,
 		PhoneNumber: request.PhoneNumber,
+		Role:        "guest",
+		Locale:      request.Locale,
+	}
+
+	if user.Locale == "" {
+		user.Locale = "id"
 	}
 
 	result := ur.DB.Create(&user)
@@ -112,7 +123,7 @@ func (ur *userRepository) GetBalance(userID int) (float64, error) {
 func (ur *userRepository) TopUpBalance(userID int, request entity.UserTopUpBalancePayload) (*entity.TopUpTransaction, error) {
 	orderID := fmt.Sprintf("TPUP-%s", uuid.New().String())
 
-	topup := ur.createTopupEntity(uint(userID), orderID, request.Amount)
+	topup := ur.createTopupEntity(uint(userID), orderID, utils.RoundPrice(request.Amount))
 
 	insertTopup := ur.DB.Save(&topup)
 
@@ -142,6 +153,67 @@ func (ur *userRepository) GetBookHistory(userID int) ([]entity.BookingHistoryRes
 	return historyBook, nil
 }
 
+func (ur *userRepository) AddFavorite(userID, hotelID int) error {
+	var hotel entity.Hotel
+
+	if result := ur.DB.First(&hotel, hotelID); result.Error != nil {
+		log.Println(result.Error)
+		return fmt.Errorf("404 | hotel not found")
+	}
+
+	favorite := entity.Favorite{UserID: uint(userID), HotelID: hotel.ID}
+
+	// Saving a hotel twice is not an error
+	result := ur.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite)
+
+	if result.Error != nil {
+		log.Println(result.Error)
+		return fmt.Errorf("500 | internal server error")
+	}
+
+	return nil
+}
+
+func (ur *userRepository) RemoveFavorite(userID, hotelID int) error {
+	result := ur.DB.Where("user_id = ? AND hotel_id = ?", userID, hotelID).Delete(&entity.Favorite{})
+
+	if result.Error != nil {
+		log.Println(result.Error)
+		return fmt.Errorf("500 | internal server error")
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("404 | hotel is not in favorites")
+	}
+
+	return nil
+}
+
+func (ur *userRepository) GetFavorites(userID int) ([]entity.GetHotelList, error) {
+	var hotels []entity.GetHotelList
+
+	// Left join so saved hotels stay listed while fully booked
+	result := ur.DB.Table("favorites").
+		Select("hotels.id, hotels.name, hotels.location, hotels.star_rating, hotels.average_rating, hotels.review_count, hotels.latitude, hotels.longitude, COALESCE(MIN(rooms.price), 0) AS price, COUNT(rooms.id) AS available_rooms").
+		Joins("JOIN hotels ON hotels.id = favorites.hotel_id").
+		Joins("LEFT JOIN rooms ON rooms.hotel_id = hotels.id AND rooms.status = ?", "Available").
+		Where("favorites.user_id = ?", userID).
+		Group("hotels.id, favorites.created_at").
+		Order("favorites.created_at DESC").
+		Scan(&hotels)
+
+	if result.Error != nil {
+		log.Println(result.Error)
+		return nil, fmt.Errorf("500 | internal server error")
+	}
+
+	for i := range hotels {
+		hotels[i].IsFavorite = true
+	}
+
+	return hotels, nil
+}
+
 func (ur *userRepository) createTopupEntity(userID uint, orderID string, amount float64) entity.TopUpTransaction {
 	return entity.TopUpTransaction{
 		UserID:  userID,
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// A transfer must be confirmed within this window or started again
+	transferConfirmWindow = 10 * time.Minute
+	// Transfers and gift code purchases a user can send per calendar day
+	dailyWalletSendLimit = 10000000.00
+	giftCodeValidity     = 365 * 24 * time.Hour
+)
+
+type WalletRepository interface {
+	CreateTransfer(int, entity.WalletTransferPayload) (*entity.WalletTransfer, error)
+	ConfirmTransfer(int, string, entity.ConfirmTransferPayload) (*entity.WalletTransfer, error)
+	GetLedger(int) ([]entity.WalletLedgerEntry, error)
+	PurchaseGiftCode(int, entity.GiftCodePayload) (*entity.GiftCode, error)
+	IssueGiftCode(int, entity.GiftCodePayload) (*entity.GiftCode, error)
+	GetGiftCodes(int) ([]entity.GiftCode, error)
+	RedeemGiftCode(int, entity.RedeemGiftCodePayload) (*entity.GiftCode, error)
+}
+
+type walletRepository struct {
+	DB *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) WalletRepository {
+	return &walletRepository{DB: db}
+}
+
+// CreateTransfer starts a transfer to the user with the given email or phone
+// number. No money moves until the sender confirms it.
+func (wr *walletRepository) CreateTransfer(senderID int, payload entity.WalletTransferPayload) (*entity.WalletTransfer, error) {
+	var sender, recipient entity.User
+
+	if err := wr.DB.Where("user_id = ?", senderID).First(&sender).Error; err != nil {
+		return nil, fmt.Errorf("404 | User not found")
+	}
+
+	query := wr.DB.Where("phone_number = ?", strings.TrimSpace(payload.Recipient))
+	if strings.Contains(payload.Recipient, "@") {
+		query = wr.DB.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(payload.Recipient)))
+	}
+
+	// Phone numbers are not unique, a number shared by several users cannot say who gets the money
+	var matches []entity.User
+	if err := query.Limit(2).Find(&matches).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("404 | Recipient not found")
+	case 1:
+		recipient = matches[0]
+	default:
+		return nil, fmt.Errorf("409 | More than one user has this phone number, use their email instead")
+	}
+
+	if recipient.UserID == sender.UserID {
+		return nil, fmt.Errorf("400 | Cannot transfer to yourself")
+	}
+
+	amount := utils.RoundPrice(payload.Amount)
+
+	// Checked again on confirmation, this only saves a pointless round trip
+	if sender.Balance < amount {
+		return nil, fmt.Errorf("400 | Insufficient balance")
+	}
+
+	if err := checkDailySendLimit(wr.DB, sender.UserID, amount); err != nil {
+		return nil, err
+	}
+
+	transfer := entity.WalletTransfer{
+		TransferID:  fmt.Sprintf("TRF-%s", uuid.New().String()),
+		SenderID:    sender.UserID,
+		RecipientID: recipient.UserID,
+		Amount:      amount,
+		Note:        strings.TrimSpace(payload.Note),
+		Status:      "pending",
+		ExpiresAt:   time.Now().Add(transferConfirmWindow),
+	}
+
+	if err := wr.DB.Create(&transfer).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	transfer.RecipientName = shortName(recipient)
+
+	return &transfer, nil
+}
+
+// ConfirmTransfer checks the sender's password and moves the money, writing a
+// ledger entry on both sides
+func (wr *walletRepository) ConfirmTransfer(senderID int, transferID string, payload entity.ConfirmTransferPayload) (*entity.WalletTransfer, error) {
+	var sender entity.User
+	var transfer entity.WalletTransfer
+
+	result := wr.DB.Where("transfer_id = ? AND sender_id = ?", transferID, senderID).First(&transfer)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Transfer not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if transfer.Status != "pending" {
+		return nil, fmt.Errorf("409 | Transfer is already %s", transfer.Status)
+	}
+
+	if time.Now().After(transfer.ExpiresAt) {
+		if err := wr.DB.Model(&transfer).Where("status = ?", "pending").Update("status", "expired").Error; err != nil {
+			return nil, fmt.Errorf("500 | %v", err)
+		}
+
+		return nil, fmt.Errorf("409 | Transfer confirmation expired, please start a new transfer")
+	}
+
+	if err := wr.DB.Where("user_id = ?", senderID).First(&sender).Error; err != nil {
+		return nil, fmt.Errorf("404 | User not found")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(sender.Password), []byte(payload.Password)); err != nil {
+		log.Println(err)
+		return nil, fmt.Errorf("401 | Invalid password")
+	}
+
+	err := wr.DB.Transaction(func(tx *gorm.DB) error {
+		var users []entity.User
+
+		// A confirmation sent twice completes the transfer only once
+		now := time.Now()
+		claimed := tx.Model(&transfer).Where("status = ?", "pending").Updates(map[string]interface{}{
+			"status":       "completed",
+			"completed_at": now,
+		})
+		if claimed.Error != nil {
+			return fmt.Errorf("500 | %v", claimed.Error)
+		}
+
+		if claimed.RowsAffected == 0 {
+			return fmt.Errorf("409 | Transfer is no longer pending")
+		}
+
+		transfer.Status = "completed"
+		transfer.CompletedAt = &now
+
+		// Locked in ID order so opposite transfers between two users cannot deadlock
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id IN ?", []uint{transfer.SenderID, transfer.RecipientID}).Order("user_id").Find(&users).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if len(users) != 2 {
+			return fmt.Errorf("404 | Recipient not found")
+		}
+
+		from, to := &users[0], &users[1]
+		if from.UserID != transfer.SenderID {
+			from, to = to, from
+		}
+
+		if from.Balance < transfer.Amount {
+			return fmt.Errorf("400 | Insufficient balance")
+		}
+
+		if err := checkDailySendLimit(tx, from.UserID, transfer.Amount); err != nil {
+			return err
+		}
+
+		if err := addLedgerEntry(tx, from, "transfer_out", -transfer.Amount, transfer.TransferID, "Transfer to "+shortName(*to)); err != nil {
+			return err
+		}
+
+		if err := addLedgerEntry(tx, to, "transfer_in", transfer.Amount, transfer.TransferID, "Transfer from "+shortName(*from)); err != nil {
+			return err
+		}
+
+		transfer.RecipientName = shortName(*to)
+
+		return queueEmail(tx, to.UserID, utils.EmailTransferReceived, utils.EmailData{
+			OrderID: transfer.TransferID,
+			Amount:  transfer.Amount,
+			Sender:  strings.TrimSpace(from.FirstName + " " + from.LastName),
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (wr *walletRepository) GetLedger(userID int) ([]entity.WalletLedgerEntry, error) {
+	var entries []entity.WalletLedgerEntry
+
+	result := wr.DB.Where("user_id = ?", userID).Order("id DESC").Find(&entries)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return entries, nil
+}
+
+// PurchaseGiftCode pays for a gift code from the buyer's wallet
+func (wr *walletRepository) PurchaseGiftCode(userID int, payload entity.GiftCodePayload) (*entity.GiftCode, error) {
+	var gift *entity.GiftCode
+
+	// Checked at the amount the gift code will carry
+	payload.Amount = utils.RoundPrice(payload.Amount)
+
+	err := wr.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&user).Error; err != nil {
+			return fmt.Errorf("404 | User not found")
+		}
+
+		if user.Balance < payload.Amount {
+			return fmt.Errorf("400 | Insufficient balance")
+		}
+
+		if err := checkDailySendLimit(tx, user.UserID, payload.Amount); err != nil {
+			return err
+		}
+
+		var err error
+		gift, err = createGiftCode(tx, user.UserID, true, payload)
+		if err != nil {
+			return err
+		}
+
+		return addLedgerEntry(tx, &user, "gift_purchase", -gift.Amount, giftReference(*gift), "Gift code purchase")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return gift, nil
+}
+
+// IssueGiftCode creates a gift code that is not paid from any wallet, e.g. for
+// a corporate account funding its employees
+func (wr *walletRepository) IssueGiftCode(adminID int, payload entity.GiftCodePayload) (*entity.GiftCode, error) {
+	return createGiftCode(wr.DB, uint(adminID), false, payload)
+}
+
+// GetGiftCodes lists the codes a user bought, or every code when userID is 0
+func (wr *walletRepository) GetGiftCodes(userID int) ([]entity.GiftCode, error) {
+	var gifts []entity.GiftCode
+
+	query := wr.DB.Order("id DESC")
+	if userID != 0 {
+		query = query.Where("issued_by = ? AND purchased = ?", userID, true)
+	}
+
+	if result := query.Find(&gifts); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return gifts, nil
+}
+
+func (wr *walletRepository) RedeemGiftCode(userID int, payload entity.RedeemGiftCodePayload) (*entity.GiftCode, error) {
+	var gift entity.GiftCode
+
+	err := wr.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", utils.NormalizeGiftCode(payload.Code)).First(&gift)
+
+		if result.Error != nil {
+			if result.Error.Error() == "record not found" {
+				return fmt.Errorf("404 | Gift code not found")
+			}
+
+			return fmt.Errorf("500 | %v", result.Error)
+		}
+
+		if gift.Status != "active" {
+			return fmt.Errorf("409 | Gift code has already been redeemed")
+		}
+
+		if time.Now().After(gift.ExpiresAt) {
+			return fmt.Errorf("400 | Gift code has expired")
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&user).Error; err != nil {
+			return fmt.Errorf("404 | User not found")
+		}
+
+		now := time.Now()
+		gift.Status = "redeemed"
+		gift.RedeemedBy = user.UserID
+		gift.RedeemedAt = &now
+
+		if err := tx.Model(&gift).Updates(map[string]interface{}{
+			"status":      gift.Status,
+			"redeemed_by": gift.RedeemedBy,
+			"redeemed_at": gift.RedeemedAt,
+		}).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return addLedgerEntry(tx, &user, "gift_redeem", gift.Amount, giftReference(gift), "Gift code redeemed")
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &gift, nil
+}
+
+func createGiftCode(tx *gorm.DB, issuedBy uint, purchased bool, payload entity.GiftCodePayload) (*entity.GiftCode, error) {
+	code, err := utils.GenerateGiftCode()
+	if err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	gift := entity.GiftCode{
+		Code:      code,
+		Amount:    utils.RoundPrice(payload.Amount),
+		Status:    "active",
+		IssuedBy:  issuedBy,
+		Purchased: purchased,
+		Note:      strings.TrimSpace(payload.Note),
+		ExpiresAt: time.Now().Add(giftCodeValidity),
+	}
+
+	if err := tx.Create(&gift).Error; err != nil {
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	return &gift, nil
+}
+
+// checkDailySendLimit counts what the user sent today, by transfer or by
+// buying gift codes, against the daily limit
+func checkDailySendLimit(tx *gorm.DB, userID uint, amount float64) error {
+	var sent float64
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	err := tx.Model(&entity.WalletLedgerEntry{}).
+		Where("user_id = ? AND entry_type IN ? AND created_at >= ?", userID, []string{"transfer_out", "gift_purchase"}, today).
+		Select("COALESCE(SUM(-amount), 0)").Scan(&sent).Error
+	if err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	if sent+amount > dailyWalletSendLimit {
+		return fmt.Errorf("400 | Daily limit of %s IDR for transfers and gift codes reached", utils.FormatAmount(dailyWalletSendLimit))
+	}
+
+	return nil
+}
+
+// adjustBalance locks the user and changes their balance through the ledger.
+// A debit larger than the balance is refused.
+func adjustBalance(tx *gorm.DB, userID uint, entryType string, amount float64, reference string, description string) error {
+	var user entity.User
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&user).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	if user.Balance+amount < 0 {
+		return fmt.Errorf("400 | Insufficient balance")
+	}
+
+	return addLedgerEntry(tx, &user, entryType, amount, reference, description)
+}
+
+// addLedgerEntry changes the balance of a locked user and records why. Every
+// change of a balance goes through here so the ledger adds up to it.
+func addLedgerEntry(tx *gorm.DB, user *entity.User, entryType string, amount float64, reference string, description string) error {
+	user.Balance = utils.RoundPrice(user.Balance + amount)
+
+	if err := tx.Model(user).Update("balance", user.Balance).Error; err != nil {
+		return fmt.Errorf("500 | Failed to update user balance: %v", err)
+	}
+
+	entry := entity.WalletLedgerEntry{
+		UserID:       user.UserID,
+		EntryType:    entryType,
+		Amount:       amount,
+		BalanceAfter: user.Balance,
+		Reference:    reference,
+		Description:  description,
+	}
+
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	return nil
+}
+
+// giftReference names a gift code in the ledger without showing the code
+func giftReference(gift entity.GiftCode) string {
+	return fmt.Sprintf("GIFT-%d", gift.ID)
+}
+
+// shortName shows enough of a user's name to recognise them, e.g. "Budi S."
+func shortName(user entity.User) string {
+	if user.LastName == "" {
+		return user.FirstName
+	}
+
+	return fmt.Sprintf("%s %s.", user.FirstName, string([]rune(user.LastName)[:1]))
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"lux-hotel/entity"
+	"lux-hotel/utils"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Deliveries are given up after this many failures, about four hours in
+const MaxWebhookAttempts = 8
+
+type WebhookRepository interface {
+	GetSubscriptions() ([]entity.WebhookSubscription, error)
+	CreateSubscription(payload entity.WebhookSubscriptionPayload) (*entity.WebhookSubscription, error)
+	UpdateSubscription(subscriptionID int, payload entity.WebhookSubscriptionPayload) (*entity.WebhookSubscription, error)
+	DeleteSubscription(subscriptionID int) error
+	GetDeliveries(subscriptionID int, status string) ([]entity.WebhookDelivery, error)
+	Redeliver(deliveryID int) (*entity.WebhookDelivery, error)
+	DeliverPending(limit int) (int, error)
+}
+
+type webhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{DB: db}
+}
+
+func (wr *webhookRepository) GetSubscriptions() ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+
+	if result := wr.DB.Order("id").Find(&subscriptions); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	// The secret is handed out once, when the subscription is created
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (wr *webhookRepository) CreateSubscription(payload entity.WebhookSubscriptionPayload) (*entity.WebhookSubscription, error) {
+	subscription := entity.WebhookSubscription{
+		Name:   payload.Name,
+		URL:    payload.URL,
+		Secret: utils.GenerateWebhookSecret(),
+		Events: payload.Events,
+		Active: payload.Active == nil || *payload.Active,
+	}
+
+	if result := wr.DB.Create(&subscription); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &subscription, nil
+}
+
+func (wr *webhookRepository) UpdateSubscription(subscriptionID int, payload entity.WebhookSubscriptionPayload) (*entity.WebhookSubscription, error) {
+	subscription, err := wr.getSubscriptionByID(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Name = payload.Name
+	subscription.URL = payload.URL
+	subscription.Events = payload.Events
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+
+	if result := wr.DB.Save(subscription); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	subscription.Secret = ""
+
+	return subscription, nil
+}
+
+func (wr *webhookRepository) DeleteSubscription(subscriptionID int) error {
+	subscription, err := wr.getSubscriptionByID(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	// Deliveries stay as a log, pending ones are dropped
+	err = wr.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.WebhookDelivery{}).Where("subscription_id = ? AND status = ?", subscription.ID, "pending").Updates(map[string]interface{}{
+			"status":     "failed",
+			"last_error": "subscription deleted",
+		}).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		if err := tx.Delete(subscription).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (wr *webhookRepository) GetDeliveries(subscriptionID int, status string) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+
+	if _, err := wr.getSubscriptionByID(subscriptionID); err != nil {
+		return nil, err
+	}
+
+	query := wr.DB.Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if result := query.Order("id DESC").Limit(100).Find(&deliveries); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues a delivery again with a fresh set of attempts
+func (wr *webhookRepository) Redeliver(deliveryID int) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+
+	result := wr.DB.First(&delivery, deliveryID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Delivery not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	if _, err := wr.getSubscriptionByID(int(delivery.SubscriptionID)); err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == "pending" {
+		return nil, fmt.Errorf("400 | Delivery is already queued")
+	}
+
+	delivery.Status = "pending"
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+
+	if result := wr.DB.Save(&delivery); result.Error != nil {
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &delivery, nil
+}
+
+// DeliverPending posts up to limit due events and returns how many got through.
+// Rows are locked while sending so several workers never send the same event.
+func (wr *webhookRepository) DeliverPending(limit int) (int, error) {
+	delivered := 0
+
+	err := wr.DB.Transaction(func(tx *gorm.DB) error {
+		var deliveries []entity.WebhookDelivery
+
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", "pending", time.Now()).
+			Order("id").Limit(limit).Find(&deliveries)
+
+		if result.Error != nil {
+			return fmt.Errorf("500 | %v", result.Error)
+		}
+
+		for _, delivery := range deliveries {
+			var subscription entity.WebhookSubscription
+			if err := tx.First(&subscription, delivery.SubscriptionID).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+
+			status, err := utils.SendWebhook(subscription.URL, subscription.Secret, delivery.Event, delivery.EventID, []byte(delivery.Payload))
+
+			updates := map[string]interface{}{
+				"attempts":        delivery.Attempts + 1,
+				"response_status": status,
+			}
+
+			if err == nil {
+				updates["status"] = "delivered"
+				updates["delivered_at"] = time.Now()
+				updates["last_error"] = ""
+				delivered++
+			} else {
+				log.Println(err)
+				updates["last_error"] = err.Error()
+
+				// Back off 1, 2, 4, 8... minutes between attempts
+				if delivery.Attempts+1 >= MaxWebhookAttempts {
+					updates["status"] = "failed"
+				} else {
+					updates["next_attempt_at"] = time.Now().Add(time.Minute << delivery.Attempts)
+				}
+			}
+
+			if err := tx.Model(&delivery).Updates(updates).Error; err != nil {
+				return fmt.Errorf("500 | %v", err)
+			}
+		}
+
+		return nil
+	})
+
+	return delivered, err
+}
+
+func (wr *webhookRepository) getSubscriptionByID(subscriptionID int) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+
+	result := wr.DB.First(&subscription, subscriptionID)
+
+	if result.Error != nil {
+		if result.Error.Error() == "record not found" {
+			return nil, fmt.Errorf("404 | Subscription not found")
+		}
+
+		return nil, fmt.Errorf("500 | %v", result.Error)
+	}
+
+	return &subscription, nil
+}
+
+// queueWebhookEvent fans an event out to every active subscription listening
+// for it. Pass the transaction of the state change so both commit together.
+func queueWebhookEvent(tx *gorm.DB, event string, data interface{}) error {
+	var subscriptions []entity.WebhookSubscription
+
+	if err := tx.Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	eventID := uuid.New().String()
+	payload, err := json.Marshal(entity.WebhookEvent{
+		ID:        eventID,
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("500 | %v", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if !slices.Contains(subscription.Events, event) {
+			continue
+		}
+
+		delivery := entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        string(payload),
+			Status:         "pending",
+			NextAttemptAt:  time.Now(),
+		}
+
+		if err := tx.Create(&delivery).Error; err != nil {
+			return fmt.Errorf("500 | %v", err)
+		}
+	}
+
+	return nil
+}
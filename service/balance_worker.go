@@ -0,0 +1,49 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"time"
+)
+
+const (
+	balanceCheckInterval = time.Hour
+	balanceBatchSize     = 50
+)
+
+type BalanceWorker interface {
+	Start()
+}
+
+type balanceWorker struct {
+	BalanceRepository repository.BalanceRepository
+}
+
+func NewBalanceWorker(balanceRepository repository.BalanceRepository) BalanceWorker {
+	return &balanceWorker{BalanceRepository: balanceRepository}
+}
+
+// Start reminds guests of open balances and cancels overdue bookings in the
+// background for the lifetime of the process
+func (bw *balanceWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(balanceCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := bw.BalanceRepository.SendBalanceReminders(balanceBatchSize); err != nil {
+				log.Println("Balance reminders failed:", err)
+			}
+
+			cancelled, err := bw.BalanceRepository.CancelOverdueBookings(balanceBatchSize)
+			if err != nil {
+				log.Println("Cancelling overdue bookings failed:", err)
+				continue
+			}
+
+			if cancelled > 0 {
+				log.Printf("Cancelled %d bookings with an overdue balance", cancelled)
+			}
+		}
+	}()
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"time"
+)
+
+const (
+	bookingHoldCheckInterval = 5 * time.Minute
+	bookingHoldBatchSize     = 100
+)
+
+type BookingHoldWorker interface {
+	Start()
+}
+
+type bookingHoldWorker struct {
+	BookingHoldRepository repository.BookingHoldRepository
+}
+
+func NewBookingHoldWorker(bookingHoldRepository repository.BookingHoldRepository) BookingHoldWorker {
+	return &bookingHoldWorker{BookingHoldRepository: bookingHoldRepository}
+}
+
+// Start expires unpaid bookings in the background for the lifetime of the
+// process, so abandoned checkouts do not keep rooms off sale
+func (bw *bookingHoldWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(bookingHoldCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			expired, err := bw.BookingHoldRepository.ExpireUnpaidBookings(bookingHoldBatchSize)
+			if err != nil {
+				log.Println("Expiring unpaid bookings failed:", err)
+				continue
+			}
+
+			if expired > 0 {
+				log.Printf("Expired %d unpaid bookings", expired)
+			}
+		}
+	}()
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarService interface {
+	GetRoomCalendar(c echo.Context) error
+	GetCalendarExport(c echo.Context) error
+	GetCalendarFeeds(c echo.Context) error
+	CreateCalendarFeed(c echo.Context) error
+	DeleteCalendarFeed(c echo.Context) error
+	SyncCalendarFeed(c echo.Context) error
+	GetAvailabilityBlocks(c echo.Context) error
+}
+
+type calendarService struct {
+	CalendarRepository repository.CalendarRepository
+}
+
+func NewCalendarService(calendarRepository repository.CalendarRepository) CalendarService {
+	return &calendarService{CalendarRepository: calendarRepository}
+}
+
+// GetRoomCalendar serves the ICS feed of a room.
+// @Summary Room availability calendar
+// @Description Exports the room's upcoming bookings as an iCalendar feed for other OTAs. The token comes from the admin calendar export endpoint.
+// @Tags hotel
+// @Produce text/calendar
+// @Param id path int true "Room ID"
+// @Param token query string true "Calendar token"
+// @Success 200 {string} string "ICS calendar"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/rooms/{id}/calendar.ics [get]
+func (cs *calendarService) GetRoomCalendar(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	calendar, err := cs.CalendarRepository.GetRoomCalendar(roomID, c.QueryParam("token"))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.Blob(200, "text/calendar; charset=utf-8", []byte(calendar))
+}
+
+// GetCalendarExport returns the ICS feed URL of a room.
+// @Summary Get room calendar feed URL
+// @Description Returns the secret ICS feed URL of a room to register on other OTAs. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Calendar feed URL retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/rooms/{id}/calendar [get]
+func (cs *calendarService) GetCalendarExport(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	export, err := cs.CalendarRepository.GetCalendarExport(roomID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    export,
+	})
+}
+
+// GetCalendarFeeds lists the external calendars imported for a room.
+// @Summary List room calendar imports
+// @Description Returns the external ICS calendars that block the room, with their last sync result. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Calendar feeds retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/rooms/{id}/calendar-feeds [get]
+func (cs *calendarService) GetCalendarFeeds(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	feeds, err := cs.CalendarRepository.GetCalendarFeeds(roomID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    feeds,
+	})
+}
+
+// CreateCalendarFeed imports an external calendar for a room.
+// @Summary Add a room calendar import
+// @Description Registers an ICS calendar by http(s) URL or local file path. Its events become availability blocks that bookings of the room cannot overlap. The calendar is imported right away and then re-read periodically. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param feed body entity.CalendarFeedPayload true "Calendar source"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Calendar feed created successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/rooms/{id}/calendar-feeds [post]
+func (cs *calendarService) CreateCalendarFeed(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.CalendarFeedPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateCalendarFeedPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	feed, err := cs.CalendarRepository.CreateCalendarFeed(roomID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Calendar feed created successfully",
+		Data:    feed,
+	})
+}
+
+// DeleteCalendarFeed stops importing an external calendar.
+// @Summary Delete a room calendar import
+// @Description Removes the calendar and the availability blocks it created. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Calendar feed ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Calendar feed deleted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Calendar feed not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/calendar-feeds/{id} [delete]
+func (cs *calendarService) DeleteCalendarFeed(c echo.Context) error {
+	feedID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := cs.CalendarRepository.DeleteCalendarFeed(feedID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Calendar feed deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SyncCalendarFeed re-reads an external calendar now.
+// @Summary Sync a room calendar import
+// @Description Imports the calendar immediately instead of waiting for the periodic sync. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Calendar feed ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Calendar feed synced successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID or calendar import failed"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Calendar feed not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/calendar-feeds/{id}/sync [post]
+func (cs *calendarService) SyncCalendarFeed(c echo.Context) error {
+	feedID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	feed, err := cs.CalendarRepository.SyncCalendarFeed(feedID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Calendar feed synced successfully",
+		Data:    feed,
+	})
+}
+
+// GetAvailabilityBlocks lists the dates a room is closed by imported calendars.
+// @Summary List room availability blocks
+// @Description Returns the current and upcoming blocks imported for the room. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Availability blocks retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/rooms/{id}/availability-blocks [get]
+func (cs *calendarService) GetAvailabilityBlocks(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	blocks, err := cs.CalendarRepository.GetAvailabilityBlocks(roomID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    blocks,
+	})
+}
+
+func validateCalendarFeedPayload(payload entity.CalendarFeedPayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("400 | name is required")
+	}
+
+	if strings.TrimSpace(payload.Source) == "" {
+		return fmt.Errorf("400 | source is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"time"
+)
+
+const calendarSyncInterval = 15 * time.Minute
+
+type CalendarWorker interface {
+	Start()
+}
+
+type calendarWorker struct {
+	CalendarRepository repository.CalendarRepository
+}
+
+func NewCalendarWorker(calendarRepository repository.CalendarRepository) CalendarWorker {
+	return &calendarWorker{CalendarRepository: calendarRepository}
+}
+
+// Start re-imports the external room calendars in the background
+func (cw *calendarWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(calendarSyncInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := cw.CalendarRepository.SyncCalendarFeeds(); err != nil {
+				log.Println("Calendar sync failed:", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ChargeService interface {
+	GetHotelCharges(c echo.Context) error
+	CreateHotelCharge(c echo.Context) error
+	UpdateHotelCharge(c echo.Context) error
+	DeleteHotelCharge(c echo.Context) error
+}
+
+type chargeService struct {
+	ChargeRepository repository.ChargeRepository
+}
+
+func NewChargeService(chargeRepository repository.ChargeRepository) ChargeService {
+	return &chargeService{ChargeRepository: chargeRepository}
+}
+
+// GetHotelCharges lists the tax and fee rules of a hotel.
+// @Summary List hotel taxes and fees
+// @Description Returns every tax and service charge rule configured for the hotel, active or not. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Charges retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/charges [get]
+func (cs *chargeService) GetHotelCharges(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	charges, err := cs.ChargeRepository.GetHotelCharges(hotelID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    charges,
+	})
+}
+
+// CreateHotelCharge adds a tax or fee rule to a hotel.
+// @Summary Create a hotel tax or fee
+// @Description Adds a percentage or fixed charge, per night or per stay, inclusive or exclusive of the room rate. Percentages are taken of the room subtotal net of every inclusive charge. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param charge body entity.HotelChargePayload true "Charge rule"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Charge created successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/charges [post]
+func (cs *chargeService) CreateHotelCharge(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelChargePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateHotelChargePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	charge, err := cs.ChargeRepository.CreateHotelCharge(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Charge created successfully",
+		Data:    charge,
+	})
+}
+
+// UpdateHotelCharge replaces a tax or fee rule.
+// @Summary Update a hotel tax or fee
+// @Description Replaces the configuration of an existing charge rule. Bookings already made keep the amounts they were quoted. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Charge ID"
+// @Param charge body entity.HotelChargePayload true "Charge rule"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Charge updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Charge not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/charges/{id} [put]
+func (cs *chargeService) UpdateHotelCharge(c echo.Context) error {
+	chargeID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelChargePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateHotelChargePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	charge, err := cs.ChargeRepository.UpdateHotelCharge(chargeID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Charge updated successfully",
+		Data:    charge,
+	})
+}
+
+// DeleteHotelCharge removes a tax or fee rule.
+// @Summary Delete a hotel tax or fee
+// @Description Removes a charge rule from its hotel. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Charge ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Charge deleted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Charge not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/charges/{id} [delete]
+func (cs *chargeService) DeleteHotelCharge(c echo.Context) error {
+	chargeID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := cs.ChargeRepository.DeleteHotelCharge(chargeID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Charge deleted successfully",
+		Data:    nil,
+	})
+}
+
+func validateHotelChargePayload(payload entity.HotelChargePayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("400 | name is required")
+	}
+
+	if payload.ChargeType != "percentage" && payload.ChargeType != "fixed" {
+		return fmt.Errorf("400 | charge type must be percentage or fixed")
+	}
+
+	if payload.Amount <= 0 {
+		return fmt.Errorf("400 | amount must be greater than 0")
+	}
+
+	if payload.ChargeType == "percentage" && payload.Amount > 100 {
+		return fmt.Errorf("400 | percentage cannot exceed 100")
+	}
+
+	if payload.Basis != "" && payload.Basis != "per_night" && payload.Basis != "per_stay" {
+		return fmt.Errorf("400 | basis must be per_night or per_stay")
+	}
+
+	if payload.Inclusive && payload.Compound {
+		return fmt.Errorf("400 | inclusive charges cannot be compound")
+	}
+
+	return nil
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HotelContentService interface {
+	UpdateHotelContent(c echo.Context) error
+	UpdateDepositPolicy(c echo.Context) error
+	AddHotelPhoto(c echo.Context) error
+	UpdateHotelPhoto(c echo.Context) error
+	ReorderHotelPhotos(c echo.Context) error
+	DeleteHotelPhoto(c echo.Context) error
+}
+
+type hotelContentService struct {
+	HotelContentRepository repository.HotelContentRepository
+}
+
+func NewHotelContentService(hotelContentRepository repository.HotelContentRepository) HotelContentService {
+	return &hotelContentService{HotelContentRepository: hotelContentRepository}
+}
+
+// UpdateHotelContent replaces the descriptive content of a hotel.
+// @Summary Update hotel content
+// @Description Replaces the hotel's name, contact details, description, star rating, check-in/check-out times, amenity tags and coordinates. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param content body entity.HotelContentPayload true "Hotel content"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Hotel updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id} [put]
+func (hs *hotelContentService) UpdateHotelContent(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelContentPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateHotelContentPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	hotel, err := hs.HotelContentRepository.UpdateHotelContent(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Hotel updated successfully",
+		Data:    hotel,
+	})
+}
+
+// UpdateDepositPolicy sets the deposit rules of a hotel.
+// @Summary Update hotel deposit policy
+// @Description Lets stays of at least deposit_min_nights be booked with deposit_percent of the total. The balance is due balance_due_days before check-in, or at the front desk when balance_at_desk is set. Bookings not paid by the due date are cancelled and keep the deposit. A deposit_percent of 0 requires full payment at booking. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param policy body entity.DepositPolicyPayload true "Deposit policy"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Deposit policy updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/deposit-policy [put]
+func (hs *hotelContentService) UpdateDepositPolicy(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.DepositPolicyPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateDepositPolicyPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	hotel, err := hs.HotelContentRepository.UpdateDepositPolicy(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Deposit policy updated successfully",
+		Data:    hotel,
+	})
+}
+
+// AddHotelPhoto uploads a photo to a hotel's gallery.
+// @Summary Upload a hotel photo
+// @Description Uploads a JPEG or PNG image (max 5 MB) to the hotel gallery and generates a thumbnail. Admin only.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param image formData file true "Photo"
+// @Param caption formData string false "Caption"
+// @Param sort_order formData int false "Position in the gallery"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Photo uploaded successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request or image"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/photos [post]
+func (hs *hotelContentService) AddHotelPhoto(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelPhotoPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "image is required",
+		})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid image",
+		})
+	}
+	defer file.Close()
+
+	photo, err := hs.HotelContentRepository.AddHotelPhoto(hotelID, file, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Photo uploaded successfully",
+		Data:    photo,
+	})
+}
+
+// UpdateHotelPhoto changes the caption and position of a photo.
+// @Summary Update a hotel photo
+// @Description Changes the caption and sort order of a gallery photo. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Photo ID"
+// @Param photo body entity.HotelPhotoPayload true "Photo details"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Photo updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Photo not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/photos/{id} [put]
+func (hs *hotelContentService) UpdateHotelPhoto(c echo.Context) error {
+	photoID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelPhotoPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	photo, err := hs.HotelContentRepository.UpdateHotelPhoto(photoID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Photo updated successfully",
+		Data:    photo,
+	})
+}
+
+// ReorderHotelPhotos sets the order of a hotel's gallery.
+// @Summary Reorder hotel photos
+// @Description Orders the gallery by the given list of photo IDs. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param order body entity.HotelPhotoOrderPayload true "Photo IDs in display order"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Photos reordered successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel or photo not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/photos/order [put]
+func (hs *hotelContentService) ReorderHotelPhotos(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HotelPhotoOrderPayload
+	if err := c.Bind(&payload); err != nil || len(payload.PhotoIDs) == 0 {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	photos, err := hs.HotelContentRepository.ReorderHotelPhotos(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Photos reordered successfully",
+		Data:    photos,
+	})
+}
+
+// DeleteHotelPhoto removes a photo and its files.
+// @Summary Delete a hotel photo
+// @Description Removes a photo from the gallery and deletes the stored image and thumbnail. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Photo ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Photo deleted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Photo not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/photos/{id} [delete]
+func (hs *hotelContentService) DeleteHotelPhoto(c echo.Context) error {
+	photoID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := hs.HotelContentRepository.DeleteHotelPhoto(photoID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Photo deleted successfully",
+		Data:    nil,
+	})
+}
+
+func validateHotelContentPayload(payload entity.HotelContentPayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("400 | name is required")
+	}
+
+	if payload.Location == "" {
+		return fmt.Errorf("400 | location is required")
+	}
+
+	if payload.StarRating < 0 || payload.StarRating > 5 {
+		return fmt.Errorf("400 | star rating must be between 0 and 5")
+	}
+
+	if _, err := time.Parse("15:04", payload.CheckInTime); err != nil {
+		return fmt.Errorf("400 | check in time must be in HH:MM format")
+	}
+
+	if _, err := time.Parse("15:04", payload.CheckOutTime); err != nil {
+		return fmt.Errorf("400 | check out time must be in HH:MM format")
+	}
+
+	if (payload.Latitude == nil) != (payload.Longitude == nil) {
+		return fmt.Errorf("400 | latitude and longitude must be set together")
+	}
+
+	if payload.Latitude != nil && (*payload.Latitude < -90 || *payload.Latitude > 90) {
+		return fmt.Errorf("400 | latitude must be between -90 and 90")
+	}
+
+	if payload.Longitude != nil && (*payload.Longitude < -180 || *payload.Longitude > 180) {
+		return fmt.Errorf("400 | longitude must be between -180 and 180")
+	}
+
+	return nil
+}
+
+func validateDepositPolicyPayload(payload entity.DepositPolicyPayload) error {
+	if payload.DepositPercent < 0 || payload.DepositPercent > 99 {
+		return fmt.Errorf("400 | deposit percent must be between 0 and 99")
+	}
+
+	if payload.DepositMinNights < 0 {
+		return fmt.Errorf("400 | deposit min nights cannot be negative")
+	}
+
+	if payload.BalanceDueDays < 0 {
+		return fmt.Errorf("400 | balance due days cannot be negative")
+	}
+
+	return nil
+}
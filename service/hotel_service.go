@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"lux-hotel/entity"
 	"lux-hotel/repository"
+	"lux-hotel/utils"
 	"strconv"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
@@ -13,7 +15,15 @@ import (
 type HotelService interface {
 	GetHotelList(c echo.Context) error
 	GetHotelDetail(c echo.Context) error
+	SearchHotels(c echo.Context) error
 	Booking(c echo.Context) error
+	Quote(c echo.Context) error
+	CancelBookingRoom(c echo.Context) error
+	AssignBookingRoom(c echo.Context) error
+	CheckIn(c echo.Context) error
+	CheckOut(c echo.Context) error
+	MarkNoShow(c echo.Context) error
+	GetBookingDetail(c echo.Context) error
 }
 
 type hotelService struct {
@@ -26,15 +36,42 @@ func NewHotelService(hotelRepository repository.HotelRepository) HotelService {
 
 // GetHotelList retrieves the list of hotels.
 // @Summary Get a list of hotels
-// @Description Fetches all hotels available in the system and returns them.
+// @Description Fetches all hotels available in the system and returns them. Passing lat and lng adds the distance to each hotel and, with radius_km, keeps only hotels within that radius. With a token, is_favorite marks the user's favourite hotels.
 // @Tags hotel
 // @Accept json
 // @Produce json
+// @Param lat query number false "Latitude of the search point"
+// @Param lng query number false "Longitude of the search point"
+// @Param radius_km query number false "Search radius in kilometres"
+// @Param min_price query number false "Minimum room price"
+// @Param max_price query number false "Maximum room price"
+// @Param sort query string false "distance, price_asc, price_desc or rating"
 // @Success 200 {object} entity.ResponseOK "Successfully retrieved hotel list"
+// @Failure 400 {object} entity.ResponseError "Invalid filter"
 // @Failure 500 {object} entity.ResponseError "Internal server error"
 // @Router /api/hotel-list [get]
 func (hs *hotelService) GetHotelList(c echo.Context) error {
-	hotels, err := hs.HotelRepository.GetHotelList()
+	var filter entity.HotelListFilter
+	if err := c.Bind(&filter); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	filter.UserID = optionalUserID(c)
+
+	if err := validateHotelListFilter(filter); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	hotels, err := hs.HotelRepository.GetHotelList(filter)
 
 	if err != nil {
 		errCode, _ := strconv.Atoi(err.Error()[:3])
@@ -55,7 +92,7 @@ func (hs *hotelService) GetHotelList(c echo.Context) error {
 
 // GetHotelDetail retrieves the details of a specific hotel.
 // @Summary Get details of a specific hotel
-// @Description Fetches the details of a hotel by its ID and returns the hotel information.
+// @Description Fetches the details of a hotel by its ID and returns the hotel information. With a token, is_favorite tells whether the user saved the hotel.
 // @Tags hotel
 // @Accept json
 // @Produce json
@@ -75,7 +112,7 @@ func (hs *hotelService) GetHotelDetail(c echo.Context) error {
 		})
 	}
 
-	hotel, err := hs.HotelRepository.GetHotelDetail(id)
+	hotel, err := hs.HotelRepository.GetHotelDetail(id, optionalUserID(c))
 
 	if err != nil {
 		errCode, _ := strconv.Atoi(err.Error()[:3])
@@ -94,9 +131,64 @@ func (hs *hotelService) GetHotelDetail(c echo.Context) error {
 	})
 }
 
+// SearchHotels searches hotels by text.
+// @Summary Search hotels
+// @Description Full-text search over hotel name, location, amenities and description with prefix matching and typo tolerance. Results are ranked and include a snippet with the matched words wrapped in <mark> tags.
+// @Tags hotel
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param limit query int false "Maximum number of results (default 20, max 50)"
+// @Success 200 {object} entity.ResponseOK "Successfully searched hotels"
+// @Failure 400 {object} entity.ResponseError "Invalid query"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/hotels/search [get]
+func (hs *hotelService) SearchHotels(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+
+	if query == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "q is required",
+		})
+	}
+
+	limit := 20
+	if c.QueryParam("limit") != "" {
+		parsed, err := strconv.Atoi(c.QueryParam("limit"))
+
+		if err != nil || parsed < 1 || parsed > 50 {
+			return c.JSON(400, entity.ResponseError{
+				Status:  400,
+				Message: "limit must be between 1 and 50",
+			})
+		}
+
+		limit = parsed
+	}
+
+	results, err := hs.HotelRepository.SearchHotels(query, limit)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    results,
+	})
+}
+
 // Booking handles hotel room booking for a user.
 // @Summary Book a room in a hotel
-// @Description Allows a user to book a room in a specified hotel. Requires a valid JWT token for authentication and hotel ID in the URL.
+// @Description Allows a user to book a room in a specified hotel. Requires a valid JWT token for authentication and hotel ID in the URL. A booking expires and releases its rooms 30 minutes after it is made unless a payment has been started.
 // @Tags hotel
 // @Accept json
 // @Produce json
@@ -157,14 +249,478 @@ func (hs *hotelService) Booking(c echo.Context) error {
 			"booking_code": response.BookingCode,
 			"check_in":     response.CheckIn,
 			"check_out":    response.CheckOut,
+			"rooms":        response.Rooms,
+			"sub_total":    response.SubTotal,
+			"promo_code":   response.PromoCode,
+			"discount":     response.Discount,
+			"charges":      response.Charges,
 			"total_price":  response.TotalPrice,
 		},
 	})
 }
 
+// Quote calculates the price of a stay without booking it.
+// @Summary Get a price quote for a room
+// @Description Calculates the room subtotal, taxes and service charges for the requested stay without creating a booking. When the hotel's deposit policy covers the stay, the deposit and the date the balance is due are included.
+// @Tags hotel
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param booking_request body entity.BookingRequest true "Booking details"
+// @Success 200 {object} entity.ResponseOK "Quote calculated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID or Invalid request"
+// @Failure 404 {object} entity.ResponseError "Hotel or room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/hotel/{id}/quote [post]
+func (hs *hotelService) Quote(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.BookingRequest
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateBookingPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	quote, err := hs.HotelRepository.Quote(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    quote,
+	})
+}
+
+// CancelBookingRoom cancels one room of a booking.
+// @Summary Cancel a room from a booking
+// @Description Removes one room from a multi-room booking before check-in and re-prices the rest. Paid bookings are refunded what was paid beyond the new total to the wallet balance. Cancelling the last room cancels the booking and refunds everything paid. Not available while a bank payment is pending.
+// @Tags hotel
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Order ID"
+// @Param line_id path int true "Booking room line ID, the id of an entry in the booking's rooms"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Room cancelled successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID or booking cannot be changed"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Booking or room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/bookings/{order_id}/rooms/{line_id}/cancel [post]
+func (hs *hotelService) CancelBookingRoom(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+	lineID, err := strconv.Atoi(c.Param("line_id"))
+
+	// Lines booked by room type have no room yet, so lines are picked by their own ID
+	if err != nil || lineID <= 0 {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	booking, refund, err := hs.HotelRepository.CancelBookingRoom(int(userID), c.Param("order_id"), lineID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Room cancelled successfully",
+		Data: map[string]interface{}{
+			"booking":       booking,
+			"refund_amount": refund,
+		},
+	})
+}
+
+// GetBookingDetail retrieves a single booking of the logged-in user.
+// @Summary Get booking detail
+// @Description Returns one booking with its hotel, rooms, guest, charges, latest payment (method, VA number and status), all payments, the amount paid and any balance still due, and a timeline of what happened to it. Usable as a receipt.
+// @Tags hotel
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Order ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Booking retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Booking not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/bookings/{order_id} [get]
+func (hs *hotelService) GetBookingDetail(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	booking, err := hs.HotelRepository.GetBookingDetail(int(userID), c.Param("order_id"))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Booking retrieved successfully",
+		Data:    booking,
+	})
+}
+
+// AssignBookingRoom assigns a physical room to a booking line.
+// @Summary Assign a room to a booking
+// @Description Puts a specific room on a booking line, typically one booked by room type. The room must be of the booked type and free for the stay. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Order ID"
+// @Param assignment body entity.AssignBookingRoomPayload true "Booking line and room"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Room assigned successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Booking or room not found"
+// @Failure 409 {object} entity.ResponseError "Room is already booked for these dates"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/bookings/{order_id}/assign-room [post]
+func (hs *hotelService) AssignBookingRoom(c echo.Context) error {
+	var payload entity.AssignBookingRoomPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if payload.BookingRoomID == 0 || payload.RoomID == 0 {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "booking room ID and room ID are required",
+		})
+	}
+
+	line, err := hs.HotelRepository.AssignBookingRoom(c.Param("order_id"), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Room assigned successfully",
+		Data:    line,
+	})
+}
+
+// CheckIn checks a guest in at the front desk.
+// @Summary Check a guest in
+// @Description Checks in a paid booking by its booking code on or after the check-in day. Records the guest's ID, assigns rooms to lines that have none yet and marks the rooms occupied. A deposit booking's outstanding balance must be collected at the desk and its method given as balance_payment_method. Staff and admin only.
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param booking_code path string true "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted"
+// @Param check_in body entity.CheckInPayload true "Guest ID and room assignments"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Guest checked in successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Booking or room not found"
+// @Failure 409 {object} entity.ResponseError "Room is occupied, not cleaned or out of order, or the balance is being paid online"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/staff/bookings/{booking_code}/check-in [post]
+func (hs *hotelService) CheckIn(c echo.Context) error {
+	staffID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	bookingCode := utils.NormalizeBookingCode(c.Param("booking_code"))
+	if !utils.ValidBookingCode(bookingCode) && !utils.LegacyBookingCode(bookingCode) {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid booking code",
+		})
+	}
+
+	var payload entity.CheckInPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	payload.IDType = strings.TrimSpace(payload.IDType)
+	payload.IDNumber = strings.TrimSpace(payload.IDNumber)
+	payload.BalancePaymentMethod = strings.ToLower(strings.TrimSpace(payload.BalancePaymentMethod))
+
+	if len(payload.BalancePaymentMethod) > 20 {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "balance payment method must be at most 20 characters",
+		})
+	}
+
+	if payload.IDType == "" || payload.IDNumber == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "ID type and ID number are required",
+		})
+	}
+
+	for _, assignment := range payload.Rooms {
+		if assignment.BookingRoomID == 0 || assignment.RoomID == 0 {
+			return c.JSON(400, entity.ResponseError{
+				Status:  400,
+				Message: "booking room ID and room ID are required",
+			})
+		}
+	}
+
+	booking, err := hs.HotelRepository.CheckIn(int(staffID), bookingCode, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Guest checked in successfully",
+		Data:    booking,
+	})
+}
+
+// CheckOut checks a guest out at the front desk.
+// @Summary Check a guest out
+// @Description Checks out an in-house booking by its booking code, releases its rooms and marks them dirty for housekeeping. A late checkout fee is added to the booking total and its invoice as a charge line and recorded as paid at the desk with the given payment method. Staff and admin only.
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param booking_code path string true "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted"
+// @Param check_out body entity.CheckOutPayload false "Late checkout fee and how it was collected"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Guest checked out successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Booking not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/staff/bookings/{booking_code}/check-out [post]
+func (hs *hotelService) CheckOut(c echo.Context) error {
+	bookingCode := utils.NormalizeBookingCode(c.Param("booking_code"))
+	if !utils.ValidBookingCode(bookingCode) && !utils.LegacyBookingCode(bookingCode) {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid booking code",
+		})
+	}
+
+	var payload entity.CheckOutPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if payload.LateCheckoutFee < 0 {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "late checkout fee cannot be negative",
+		})
+	}
+
+	if payload.LateCheckoutFee > 0 && payload.PaymentMethod == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "payment method is required with a late checkout fee",
+		})
+	}
+
+	booking, err := hs.HotelRepository.CheckOut(bookingCode, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Guest checked out successfully",
+		Data:    booking,
+	})
+}
+
+// MarkNoShow marks a booking whose guest never arrived.
+// @Summary Mark a booking as no-show
+// @Description Marks a paid booking as no-show by its booking code once its check-in day has passed. Staff and admin only.
+// @Tags staff
+// @Produce json
+// @Param booking_code path string true "Booking code, case and dashes are ignored. Codes issued before the current format are still accepted"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Booking marked as no-show"
+// @Failure 400 {object} entity.ResponseError "Booking cannot be marked as no-show"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Booking not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/staff/bookings/{booking_code}/no-show [post]
+func (hs *hotelService) MarkNoShow(c echo.Context) error {
+	bookingCode := utils.NormalizeBookingCode(c.Param("booking_code"))
+	if !utils.ValidBookingCode(bookingCode) && !utils.LegacyBookingCode(bookingCode) {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid booking code",
+		})
+	}
+
+	booking, err := hs.HotelRepository.MarkNoShow(bookingCode)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Booking marked as no-show",
+		Data:    booking,
+	})
+}
+
+// optionalUserID returns the logged-in user on public endpoints, or 0 for anonymous requests
+func optionalUserID(c echo.Context) uint {
+	claims, ok := c.Get("user").(jwt.MapClaims)
+	if !ok {
+		return 0
+	}
+
+	userID, _ := claims["user_id"].(float64)
+
+	return uint(userID)
+}
+
+func validateHotelListFilter(filter entity.HotelListFilter) error {
+	hasPoint := filter.Latitude != nil && filter.Longitude != nil
+
+	if (filter.Latitude == nil) != (filter.Longitude == nil) {
+		return fmt.Errorf("400 | lat and lng must be given together")
+	}
+
+	if hasPoint && (*filter.Latitude < -90 || *filter.Latitude > 90 || *filter.Longitude < -180 || *filter.Longitude > 180) {
+		return fmt.Errorf("400 | lat or lng is out of range")
+	}
+
+	if filter.RadiusKm < 0 || filter.RadiusKm > 1000 {
+		return fmt.Errorf("400 | radius must be between 0 and 1000 km")
+	}
+
+	if filter.RadiusKm > 0 && !hasPoint {
+		return fmt.Errorf("400 | radius requires lat and lng")
+	}
+
+	if filter.MinPrice < 0 || filter.MaxPrice < 0 || (filter.MaxPrice > 0 && filter.MaxPrice < filter.MinPrice) {
+		return fmt.Errorf("400 | invalid price range")
+	}
+
+	switch filter.Sort {
+	case "", "price_asc", "price_desc", "rating":
+	case "distance":
+		if !hasPoint {
+			return fmt.Errorf("400 | sorting by distance requires lat and lng")
+		}
+	default:
+		return fmt.Errorf("400 | sort must be distance, price_asc, price_desc or rating")
+	}
+
+	return nil
+}
+
 func validateBookingPayload(payload entity.BookingRequest) error {
-	if payload.RoomID == 0 {
-		return fmt.Errorf("400 | room ID is required")
+	if len(payload.Rooms) == 0 && payload.RoomID == 0 && payload.RoomTypeID == 0 {
+		return fmt.Errorf("400 | room ID or room type ID is required")
+	}
+
+	if payload.Adults < 0 || payload.Children < 0 {
+		return fmt.Errorf("400 | guest count cannot be negative")
+	}
+
+	seenRooms := map[uint]bool{}
+	for _, room := range payload.Rooms {
+		if room.RoomID == 0 && room.RoomTypeID == 0 {
+			return fmt.Errorf("400 | room ID or room type ID is required")
+		}
+
+		// Lines are only saved once all are priced, so the clash checks cannot catch a repeat
+		if room.RoomID != 0 && seenRooms[room.RoomID] {
+			return fmt.Errorf("400 | room %d is listed more than once", room.RoomID)
+		}
+		seenRooms[room.RoomID] = true
+
+		if room.Adults < 1 {
+			return fmt.Errorf("400 | each room needs at least 1 adult")
+		}
+
+		if room.Children < 0 {
+			return fmt.Errorf("400 | guest count cannot be negative")
+		}
 	}
 
 	if payload.CheckIn == "" {
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HousekeepingService interface {
+	UpdateRoomHousekeeping(c echo.Context) error
+	GetHousekeepingTasks(c echo.Context) error
+}
+
+type housekeepingService struct {
+	HousekeepingRepository repository.HousekeepingRepository
+}
+
+func NewHousekeepingService(housekeepingRepository repository.HousekeepingRepository) HousekeepingService {
+	return &housekeepingService{HousekeepingRepository: housekeepingRepository}
+}
+
+// UpdateRoomHousekeeping sets the housekeeping status of a room.
+// @Summary Update the housekeeping status of a room
+// @Description Moves a room between clean, dirty, inspected and out_of_order. Dirty rooms can only be cleaned or taken out of order, and only clean rooms can be inspected. Staff and admin only.
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param housekeeping body entity.HousekeepingPayload true "Housekeeping status"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Housekeeping status updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/staff/rooms/{id}/housekeeping [put]
+func (hs *housekeepingService) UpdateRoomHousekeeping(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.HousekeepingPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if !slices.Contains([]string{"clean", "dirty", "inspected", "out_of_order"}, payload.Status) {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "status must be clean, dirty, inspected or out_of_order",
+		})
+	}
+
+	room, err := hs.HousekeepingRepository.UpdateRoomHousekeeping(roomID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Housekeeping status updated successfully",
+		Data:    room,
+	})
+}
+
+// GetHousekeepingTasks returns a hotel's housekeeping list for a day.
+// @Summary Get the daily housekeeping task list
+// @Description Lists the rooms of a hotel that need cleaning, stay-over service, inspection before an arrival or maintenance on a day. Rooms with arrivals come first. Staff and admin only.
+// @Tags staff
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param date query string false "Day as YYYY-MM-DD, defaults to today"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Housekeeping tasks retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/staff/hotels/{id}/housekeeping [get]
+func (hs *housekeepingService) GetHousekeepingTasks(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	date := c.QueryParam("date")
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "date must be in YYYY-MM-DD format",
+		})
+	}
+
+	tasks, err := hs.HousekeepingRepository.GetHousekeepingTasks(hotelID, date)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Housekeeping tasks retrieved successfully",
+		Data:    tasks,
+	})
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"lux-hotel/utils"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type InvoiceService interface {
+	DownloadInvoice(c echo.Context) error
+}
+
+type invoiceService struct {
+	InvoiceRepository repository.InvoiceRepository
+}
+
+func NewInvoiceService(invoiceRepository repository.InvoiceRepository) InvoiceService {
+	return &invoiceService{InvoiceRepository: invoiceRepository}
+}
+
+// DownloadInvoice returns the PDF invoice of a paid booking.
+// @Summary Download booking invoice
+// @Description Returns the invoice and booking confirmation of a paid booking as a PDF. Invoice numbers run sequentially per hotel and the room lines and totals are stored when the invoice is issued, so later changes to the booking do not alter them. Every settled payment is listed, with the amount paid and any balance still due. Available to the guest and to admins.
+// @Tags hotel
+// @Produce application/pdf
+// @Param order_id path string true "Order ID"
+// @Security ApiKeyAuth
+// @Success 200 {file} file "Invoice PDF"
+// @Failure 400 {object} entity.ResponseError "Booking is not paid"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Booking not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/bookings/{order_id}/invoice [get]
+func (is *invoiceService) DownloadInvoice(c echo.Context) error {
+	claims := c.Get("user").(jwt.MapClaims)
+	userID := claims["user_id"].(float64)
+	role, _ := claims["role"].(string)
+
+	document, err := is.InvoiceRepository.GetInvoice(int(userID), role, c.Param("order_id"))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", document.Invoice.InvoiceNumber+".pdf"))
+
+	return c.Blob(200, "application/pdf", utils.RenderInvoicePDF(*document))
+}
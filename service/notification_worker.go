@@ -0,0 +1,49 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"lux-hotel/utils"
+	"time"
+)
+
+const (
+	notificationPollInterval = 10 * time.Second
+	notificationBatchSize    = 20
+)
+
+type NotificationWorker interface {
+	Start()
+}
+
+type notificationWorker struct {
+	NotificationRepository repository.NotificationRepository
+	Mailer                 utils.Mailer
+}
+
+func NewNotificationWorker(notificationRepository repository.NotificationRepository, mailer utils.Mailer) NotificationWorker {
+	return &notificationWorker{NotificationRepository: notificationRepository, Mailer: mailer}
+}
+
+// Start delivers the outbox in the background for the lifetime of the process
+func (nw *notificationWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(notificationPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// Keep going while full batches come back so a backlog drains quickly
+			for {
+				sent, err := nw.NotificationRepository.DeliverPending(notificationBatchSize, nw.Mailer)
+				if err != nil {
+					log.Println("Notification delivery failed:", err)
+					break
+				}
+
+				if sent < notificationBatchSize {
+					break
+				}
+			}
+		}
+	}()
+}
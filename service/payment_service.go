@@ -3,6 +3,7 @@ package service
 import (
 	"lux-hotel/entity"
 	"lux-hotel/repository"
+	"lux-hotel/utils"
 	"strconv"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,6 +12,7 @@ import (
 
 type PaymentService interface {
 	Payment(c echo.Context) error
+	GetPaymentMethods(c echo.Context) error
 }
 
 type paymentService struct {
@@ -23,7 +25,7 @@ func NewPaymentService(paymentRepository repository.PaymentRepository) PaymentSe
 
 // Payment processes a payment order for a user.
 // @Summary Process a payment order
-// @Description Processes a payment order, requiring a valid JWT token for authentication. The request body should contain payment details.
+// @Description Processes a payment order, requiring a valid JWT token for authentication. The request body should contain payment details. payment_method is a code from /api/payment-methods, credit_card also needs a Midtrans card_token. The method's fee is added to the amount due and the response carries the VA, bill key, QR code, deeplink or 3DS redirect the method needs. Calling it again with the same bank returns the pending virtual account; another method cancels the pending charge and starts a new attempt while the booking stays held. Set deposit to pay only the deposit of a booking that offers one; paying a confirmed booking with an open balance pays the balance. Set use_wallet to take what the wallet balance covers and charge the rest with payment_method; the wallet part is held until that charge settles and returned if it expires, is cancelled or is refused.
 // @Tags payment
 // @Accept json
 // @Produce json
@@ -32,6 +34,7 @@ func NewPaymentService(paymentRepository repository.PaymentRepository) PaymentSe
 // @Success 200 {object} entity.ResponseOK "Payment processed successfully"
 // @Failure 400 {object} entity.ResponseError "Invalid request"
 // @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 409 {object} entity.ResponseError "Pending payment could not be cancelled"
 // @Failure 500 {object} entity.ResponseError "Internal server error"
 // @Router /api/order/payment [post]
 func (ps *paymentService) Payment(c echo.Context) error {
@@ -63,3 +66,28 @@ func (ps *paymentService) Payment(c echo.Context) error {
 		Data:    response,
 	})
 }
+
+// GetPaymentMethods lists the payment methods guests can choose from.
+// @Summary Get payment methods
+// @Description Lists the enabled payment methods with display name, logo, fee and the amounts they accept. The code goes into payment_method of /api/order/payment.
+// @Tags payment
+// @Produce json
+// @Success 200 {object} entity.ResponseOK "Payment methods retrieved successfully"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/payment-methods [get]
+func (ps *paymentService) GetPaymentMethods(c echo.Context) error {
+	methods, err := utils.PaymentMethods()
+
+	if err != nil {
+		return c.JSON(500, entity.ResponseError{
+			Status:  500,
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Payment methods retrieved successfully",
+		Data:    methods,
+	})
+}
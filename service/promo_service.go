@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PromoService interface {
+	GetPromoCodes(c echo.Context) error
+	CreatePromoCode(c echo.Context) error
+	UpdatePromoCode(c echo.Context) error
+	GetPromoRedemptions(c echo.Context) error
+}
+
+type promoService struct {
+	PromoRepository repository.PromoRepository
+}
+
+func NewPromoService(promoRepository repository.PromoRepository) PromoService {
+	return &promoService{PromoRepository: promoRepository}
+}
+
+// GetPromoCodes lists every promo code.
+// @Summary List promo codes
+// @Description Returns all promo codes with their hotel restrictions. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Promo codes retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/promos [get]
+func (ps *promoService) GetPromoCodes(c echo.Context) error {
+	promos, err := ps.PromoRepository.GetPromoCodes()
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    promos,
+	})
+}
+
+// CreatePromoCode creates a new promo code.
+// @Summary Create a promo code
+// @Description Creates a percentage or fixed discount code with optional validity window, usage limits, minimum stay and hotel restrictions. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param promo body entity.PromoCodePayload true "Promo code"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Promo code created successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 409 {object} entity.ResponseError "Promo code already exists"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/promos [post]
+func (ps *promoService) CreatePromoCode(c echo.Context) error {
+	var payload entity.PromoCodePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if strings.TrimSpace(payload.Code) == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "code is required",
+		})
+	}
+
+	if err := validatePromoCodePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	promo, err := ps.PromoRepository.CreatePromoCode(payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Promo code created successfully",
+		Data:    promo,
+	})
+}
+
+// UpdatePromoCode updates a promo code.
+// @Summary Update a promo code
+// @Description Replaces the rules of a promo code. The code itself cannot be changed; set active to false to retire it. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo code ID"
+// @Param promo body entity.PromoCodePayload true "Promo code"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Promo code updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Promo code not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/promos/{id} [put]
+func (ps *promoService) UpdatePromoCode(c echo.Context) error {
+	promoID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.PromoCodePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validatePromoCodePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	promo, err := ps.PromoRepository.UpdatePromoCode(promoID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Promo code updated successfully",
+		Data:    promo,
+	})
+}
+
+// GetPromoRedemptions lists the usage of a promo code.
+// @Summary List promo code redemptions
+// @Description Returns every booking that used the promo code with its status: held while unpaid, redeemed once paid, released when the booking expired or was cancelled. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo code ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Redemptions retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Promo code not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/promos/{id}/redemptions [get]
+func (ps *promoService) GetPromoRedemptions(c echo.Context) error {
+	promoID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	redemptions, err := ps.PromoRepository.GetPromoRedemptions(promoID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    redemptions,
+	})
+}
+
+func validatePromoCodePayload(payload entity.PromoCodePayload) error {
+	if len(strings.TrimSpace(payload.Code)) > 30 {
+		return fmt.Errorf("400 | code cannot be longer than 30 characters")
+	}
+
+	if payload.DiscountType != "percentage" && payload.DiscountType != "fixed" {
+		return fmt.Errorf("400 | discount type must be percentage or fixed")
+	}
+
+	if payload.DiscountValue <= 0 {
+		return fmt.Errorf("400 | discount value must be greater than 0")
+	}
+
+	if payload.DiscountType == "percentage" && payload.DiscountValue > 100 {
+		return fmt.Errorf("400 | percentage cannot exceed 100")
+	}
+
+	if payload.MaxDiscount < 0 || payload.UsageLimit < 0 || payload.PerUserLimit < 0 || payload.MinNights < 0 {
+		return fmt.Errorf("400 | limits cannot be negative")
+	}
+
+	if payload.ValidFrom != nil && payload.ValidUntil != nil && payload.ValidUntil.Before(*payload.ValidFrom) {
+		return fmt.Errorf("400 | valid until cannot be before valid from")
+	}
+
+	return nil
+}
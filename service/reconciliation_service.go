@@ -0,0 +1,144 @@
+package service
+
+import (
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReconciliationService interface {
+	RunReconciliation(c echo.Context) error
+	GetDiscrepancies(c echo.Context) error
+	ResolveDiscrepancy(c echo.Context) error
+}
+
+type reconciliationService struct {
+	ReconciliationRepository repository.ReconciliationRepository
+}
+
+func NewReconciliationService(reconciliationRepository repository.ReconciliationRepository) ReconciliationService {
+	return &reconciliationService{ReconciliationRepository: reconciliationRepository}
+}
+
+// RunReconciliation reconciles stale pending payments right away.
+// @Summary Run payment reconciliation
+// @Description Looks up pending payments older than 15 minutes at Midtrans, applies the status a missed callback would have and reports discrepancies. The same job runs every 10 minutes. Admin only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Reconciliation completed"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/reconciliation/run [post]
+func (rs *reconciliationService) RunReconciliation(c echo.Context) error {
+	report, err := rs.ReconciliationRepository.Reconcile(reconciliationBatchSize)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Reconciliation completed",
+		Data:    report,
+	})
+}
+
+// GetDiscrepancies lists what reconciliation found.
+// @Summary Get payment discrepancies
+// @Description Lists discrepancies between payments and Midtrans, newest first: missed settlements, amount mismatches, duplicate payments and payments Midtrans does not know. Admin only.
+// @Tags admin
+// @Produce json
+// @Param resolved query bool false "Only resolved or only open discrepancies"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Discrepancies retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/payment-discrepancies [get]
+func (rs *reconciliationService) GetDiscrepancies(c echo.Context) error {
+	var resolved *bool
+
+	if value := c.QueryParam("resolved"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.JSON(400, entity.ResponseError{
+				Status:  400,
+				Message: "resolved must be true or false",
+			})
+		}
+
+		resolved = &parsed
+	}
+
+	discrepancies, err := rs.ReconciliationRepository.GetDiscrepancies(resolved)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Discrepancies retrieved successfully",
+		Data:    discrepancies,
+	})
+}
+
+// ResolveDiscrepancy closes a discrepancy once it has been handled.
+// @Summary Resolve a payment discrepancy
+// @Description Marks a discrepancy as handled, e.g. after refunding a duplicate payment. Admin only.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Discrepancy ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Discrepancy resolved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Discrepancy not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/payment-discrepancies/{id}/resolve [put]
+func (rs *reconciliationService) ResolveDiscrepancy(c echo.Context) error {
+	discrepancyID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	discrepancy, err := rs.ReconciliationRepository.ResolveDiscrepancy(discrepancyID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Discrepancy resolved successfully",
+		Data:    discrepancy,
+	})
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"time"
+)
+
+const (
+	reconciliationInterval  = 10 * time.Minute
+	reconciliationBatchSize = 50
+)
+
+type ReconciliationWorker interface {
+	Start()
+}
+
+type reconciliationWorker struct {
+	ReconciliationRepository repository.ReconciliationRepository
+}
+
+func NewReconciliationWorker(reconciliationRepository repository.ReconciliationRepository) ReconciliationWorker {
+	return &reconciliationWorker{ReconciliationRepository: reconciliationRepository}
+}
+
+// Start checks stale pending payments against Midtrans in the background for the lifetime of the process
+func (rw *reconciliationWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(reconciliationInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := rw.ReconciliationRepository.Reconcile(reconciliationBatchSize)
+			if err != nil {
+				log.Println("Payment reconciliation failed:", err)
+				continue
+			}
+
+			if len(report.Discrepancies) > 0 {
+				log.Printf("Payment reconciliation found %d discrepancies", len(report.Discrepancies))
+			}
+		}
+	}()
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewService interface {
+	CreateReview(c echo.Context) error
+	GetHotelReviews(c echo.Context) error
+	GetReviews(c echo.Context) error
+	ModerateReview(c echo.Context) error
+	ReplyReview(c echo.Context) error
+}
+
+type reviewService struct {
+	ReviewRepository repository.ReviewRepository
+}
+
+func NewReviewService(reviewRepository repository.ReviewRepository) ReviewService {
+	return &reviewService{ReviewRepository: reviewRepository}
+}
+
+// CreateReview lets a guest review a completed stay.
+// @Summary Review a stay
+// @Description Adds a review with an overall rating and optional category ratings (1-5) for a paid booking whose check-out date has passed. One review per booking; reviews are published after moderation.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param order_id path string true "Order ID"
+// @Param review body entity.ReviewPayload true "Review"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Review submitted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request or stay not completed"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Booking not found"
+// @Failure 409 {object} entity.ResponseError "Booking has already been reviewed"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/bookings/{order_id}/review [post]
+func (rs *reviewService) CreateReview(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.ReviewPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateReviewPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	review, err := rs.ReviewRepository.CreateReview(int(userID), c.Param("order_id"), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Review submitted successfully",
+		Data:    review,
+	})
+}
+
+// GetHotelReviews lists the published reviews of a hotel.
+// @Summary Get hotel reviews
+// @Description Returns approved reviews of a hotel, newest first, with overall and category rating averages.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Success 200 {object} entity.ResponseOK "Successfully retrieved reviews"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/hotel/{id}/reviews [get]
+func (rs *reviewService) GetHotelReviews(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	reviews, err := rs.ReviewRepository.GetHotelReviews(hotelID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    reviews,
+	})
+}
+
+// GetReviews lists reviews for moderation.
+// @Summary List reviews
+// @Description Returns reviews of every hotel, newest first, optionally filtered by moderation status. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "pending, approved or rejected"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Successfully retrieved reviews"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/reviews [get]
+func (rs *reviewService) GetReviews(c echo.Context) error {
+	reviews, err := rs.ReviewRepository.GetReviews(c.QueryParam("status"))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    reviews,
+	})
+}
+
+// ModerateReview approves or rejects a review.
+// @Summary Moderate a review
+// @Description Approves or rejects a review. Only approved reviews are published and counted in the hotel's average rating. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param moderation body entity.ReviewModerationPayload true "Moderation decision"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Review moderated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Review not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/reviews/{id}/moderate [put]
+func (rs *reviewService) ModerateReview(c echo.Context) error {
+	reviewID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.ReviewModerationPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if payload.Status != "approved" && payload.Status != "rejected" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "status must be approved or rejected",
+		})
+	}
+
+	review, err := rs.ReviewRepository.ModerateReview(reviewID, payload.Status)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Review moderated successfully",
+		Data:    review,
+	})
+}
+
+// ReplyReview posts the hotel's reply to a review.
+// @Summary Reply to a review
+// @Description Sets or replaces the hotel's public reply to a review. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param reply body entity.ReviewReplyPayload true "Reply"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Reply saved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Review not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/reviews/{id}/reply [post]
+func (rs *reviewService) ReplyReview(c echo.Context) error {
+	reviewID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.ReviewReplyPayload
+	if err := c.Bind(&payload); err != nil || strings.TrimSpace(payload.Reply) == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "reply is required",
+		})
+	}
+
+	review, err := rs.ReviewRepository.ReplyReview(reviewID, strings.TrimSpace(payload.Reply))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Reply saved successfully",
+		Data:    review,
+	})
+}
+
+func validateReviewPayload(payload entity.ReviewPayload) error {
+	if payload.OverallRating < 1 || payload.OverallRating > 5 {
+		return fmt.Errorf("400 | overall rating must be between 1 and 5")
+	}
+
+	for _, rating := range []int{payload.CleanlinessRating, payload.ServiceRating, payload.LocationRating, payload.ValueRating} {
+		if rating < 0 || rating > 5 {
+			return fmt.Errorf("400 | category ratings must be between 1 and 5")
+		}
+	}
+
+	if len(payload.Title) > 100 {
+		return fmt.Errorf("400 | title cannot be longer than 100 characters")
+	}
+
+	return nil
+}
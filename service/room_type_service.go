@@ -0,0 +1,267 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoomTypeService interface {
+	CreateRoomType(c echo.Context) error
+	UpdateRoomType(c echo.Context) error
+	DeleteRoomType(c echo.Context) error
+	SetRoomType(c echo.Context) error
+}
+
+type roomTypeService struct {
+	RoomTypeRepository repository.RoomTypeRepository
+}
+
+func NewRoomTypeService(roomTypeRepository repository.RoomTypeRepository) RoomTypeService {
+	return &roomTypeService{RoomTypeRepository: roomTypeRepository}
+}
+
+// CreateRoomType adds a room type to a hotel's catalog.
+// @Summary Create a room type
+// @Description Adds a room type with capacity, beds, size, amenities, photos and base rate. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Hotel ID"
+// @Param room_type body entity.RoomTypePayload true "Room type"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Room type created successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/hotels/{id}/room-types [post]
+func (rs *roomTypeService) CreateRoomType(c echo.Context) error {
+	hotelID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.RoomTypePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateRoomTypePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	roomType, err := rs.RoomTypeRepository.CreateRoomType(hotelID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Room type created successfully",
+		Data:    roomType,
+	})
+}
+
+// UpdateRoomType replaces a room type.
+// @Summary Update a room type
+// @Description Replaces the details of a room type. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room type ID"
+// @Param room_type body entity.RoomTypePayload true "Room type"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Room type updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room type not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/room-types/{id} [put]
+func (rs *roomTypeService) UpdateRoomType(c echo.Context) error {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.RoomTypePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateRoomTypePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	roomType, err := rs.RoomTypeRepository.UpdateRoomType(roomTypeID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Room type updated successfully",
+		Data:    roomType,
+	})
+}
+
+// DeleteRoomType removes a room type.
+// @Summary Delete a room type
+// @Description Removes a room type that no room references any more and no upcoming booking holds. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room type ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Room type deleted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID or room type has upcoming bookings"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room type not found"
+// @Failure 409 {object} entity.ResponseError "Room type still has rooms"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/room-types/{id} [delete]
+func (rs *roomTypeService) DeleteRoomType(c echo.Context) error {
+	roomTypeID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := rs.RoomTypeRepository.DeleteRoomType(roomTypeID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Room type deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SetRoomType links a physical room to a room type.
+// @Summary Set the room type of a room
+// @Description Links a room to a room type of the same hotel, or unlinks it when room_type_id is null. A room cannot leave a type whose remaining rooms would be fewer than its upcoming bookings on any night. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param room_type body entity.RoomTypeAssignPayload true "Room type"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Room updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request or the type would be overbooked"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Room or room type not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/rooms/{id}/room-type [put]
+func (rs *roomTypeService) SetRoomType(c echo.Context) error {
+	roomID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.RoomTypeAssignPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	room, err := rs.RoomTypeRepository.SetRoomType(roomID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Room updated successfully",
+		Data:    room,
+	})
+}
+
+func validateRoomTypePayload(payload entity.RoomTypePayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("400 | name is required")
+	}
+
+	if payload.MaxAdults < 1 {
+		return fmt.Errorf("400 | max adults must be at least 1")
+	}
+
+	if payload.MaxChildren < 0 || payload.BedCount < 0 || payload.SizeSqm < 0 {
+		return fmt.Errorf("400 | capacity, beds and size cannot be negative")
+	}
+
+	if payload.BaseRate <= 0 {
+		return fmt.Errorf("400 | base rate must be greater than 0")
+	}
+
+	return nil
+}
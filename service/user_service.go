@@ -20,6 +20,9 @@ type UserService interface {
 	TopUpBalance(c echo.Context) error
 	GetBookHistory(c echo.Context) error
 	GetUserByEmail(c echo.Context) error
+	AddFavorite(c echo.Context) error
+	RemoveFavorite(c echo.Context) error
+	GetFavorites(c echo.Context) error
 }
 
 type userService struct {
@@ -291,6 +294,123 @@ func (us *userService) GetBookHistory(c echo.Context) error {
 	})
 }
 
+// AddFavorite saves a hotel to the logged-in user's favourites.
+// @Summary Add a favourite hotel
+// @Description Saves a hotel to the user's wishlist. Adding a hotel that is already saved succeeds without changes.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param hotel_id path int true "Hotel ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Hotel added to favorites"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Hotel not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/users/favorites/{hotel_id} [post]
+func (us *userService) AddFavorite(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+	hotelID, err := strconv.Atoi(c.Param("hotel_id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := us.UserRepository.AddFavorite(int(userID), hotelID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Hotel added to favorites",
+		Data:    nil,
+	})
+}
+
+// RemoveFavorite removes a hotel from the logged-in user's favourites.
+// @Summary Remove a favourite hotel
+// @Description Removes a hotel from the user's wishlist.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param hotel_id path int true "Hotel ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Hotel removed from favorites"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Hotel is not in favorites"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/users/favorites/{hotel_id} [delete]
+func (us *userService) RemoveFavorite(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+	hotelID, err := strconv.Atoi(c.Param("hotel_id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := us.UserRepository.RemoveFavorite(int(userID), hotelID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Hotel removed from favorites",
+		Data:    nil,
+	})
+}
+
+// GetFavorites lists the logged-in user's favourite hotels.
+// @Summary Get favourite hotels
+// @Description Returns the user's saved hotels, most recently saved first, in the same shape as the hotel list.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "User favorites retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/users/favorites [get]
+func (us *userService) GetFavorites(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	favorites, err := us.UserRepository.GetFavorites(int(userID))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "User favorites retrieved successfully",
+		Data:    favorites,
+	})
+}
+
 func validateRegisterPayload(request entity.UserRegisterPayload) error {
 	if request.FirstName == "" {
 		return fmt.Errorf("400 | first name is required")
@@ -328,6 +448,10 @@ func validateRegisterPayload(request entity.UserRegisterPayload) error {
 		return fmt.Errorf("400 | phone number is not valid")
 	}
 
+	if request.Locale != "" && request.Locale != "id" && request.Locale != "en" {
+		return fmt.Errorf("400 | locale must be id or en")
+	}
+
 	return nil
 }
 
@@ -355,6 +479,7 @@ func generateJWTToken(user *entity.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.UserID,
 		"email":   user.Email,
+		"role":    user.Role,
 		"exp":     time.Now().Add(time.Hour * 1).Unix(),
 	})
 
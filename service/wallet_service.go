@@ -0,0 +1,431 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type WalletService interface {
+	CreateTransfer(c echo.Context) error
+	ConfirmTransfer(c echo.Context) error
+	GetLedger(c echo.Context) error
+	PurchaseGiftCode(c echo.Context) error
+	GetMyGiftCodes(c echo.Context) error
+	RedeemGiftCode(c echo.Context) error
+	IssueGiftCode(c echo.Context) error
+	GetGiftCodes(c echo.Context) error
+}
+
+type walletService struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewWalletService(walletRepository repository.WalletRepository) WalletService {
+	return &walletService{WalletRepository: walletRepository}
+}
+
+// CreateTransfer starts a balance transfer to another user.
+// @Summary Start a wallet transfer
+// @Description Starts sending balance to the user with the given email or phone number. Returns the shortened recipient name to check; nothing is sent until the transfer is confirmed with the sender's password within 10 minutes. A transfer is between 10.000 and 5.000.000 IDR, and transfers plus gift code purchases are limited to 10.000.000 IDR a day.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param transfer body entity.WalletTransferPayload true "Transfer"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Transfer created, waiting for confirmation"
+// @Failure 400 {object} entity.ResponseError "Invalid request, insufficient balance or daily limit reached"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Recipient not found"
+// @Failure 409 {object} entity.ResponseError "Phone number belongs to more than one user"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/transfers [post]
+func (ws *walletService) CreateTransfer(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.WalletTransferPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateWalletTransferPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	transfer, err := ws.WalletRepository.CreateTransfer(int(userID), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Transfer created, confirm it to send the balance",
+		Data:    transfer,
+	})
+}
+
+// ConfirmTransfer sends the balance of a started transfer.
+// @Summary Confirm a wallet transfer
+// @Description Confirms a pending transfer with the sender's password. The balance moves at once, both sides get a ledger entry and the recipient is emailed.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Param confirmation body entity.ConfirmTransferPayload true "Sender password"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Transfer completed successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request, insufficient balance or daily limit reached"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access or invalid password"
+// @Failure 404 {object} entity.ResponseError "Transfer not found"
+// @Failure 409 {object} entity.ResponseError "Transfer is no longer pending or has expired"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/transfers/{transfer_id}/confirm [post]
+func (ws *walletService) ConfirmTransfer(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.ConfirmTransferPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if payload.Password == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "password is required",
+		})
+	}
+
+	transfer, err := ws.WalletRepository.ConfirmTransfer(int(userID), c.Param("transfer_id"), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Transfer completed successfully",
+		Data:    transfer,
+	})
+}
+
+// GetLedger lists the wallet movements of the logged-in user.
+// @Summary Get the wallet ledger
+// @Description Lists every change of the wallet balance, newest first, with the balance after each entry: top-ups, booking payments and refunds, amounts held for split payments and their release, transfers and gift codes.
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Ledger retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/ledger [get]
+func (ws *walletService) GetLedger(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	entries, err := ws.WalletRepository.GetLedger(int(userID))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    entries,
+	})
+}
+
+// PurchaseGiftCode buys a gift code with the wallet balance.
+// @Summary Buy a gift code
+// @Description Buys a gift balance code worth between 50.000 and 5.000.000 IDR, paid from the wallet. Anyone holding the code can redeem it into their wallet within a year. Counts towards the daily limit of 10.000.000 IDR.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param gift body entity.GiftCodePayload true "Gift code"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Gift code purchased successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request, insufficient balance or daily limit reached"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/gift-codes [post]
+func (ws *walletService) PurchaseGiftCode(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.GiftCodePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateGiftCodePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	gift, err := ws.WalletRepository.PurchaseGiftCode(int(userID), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Gift code purchased successfully",
+		Data:    gift,
+	})
+}
+
+// GetMyGiftCodes lists the gift codes the logged-in user bought.
+// @Summary Get purchased gift codes
+// @Description Lists the gift codes bought by the logged-in user, newest first, and whether they were redeemed.
+// @Tags user
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Gift codes retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/gift-codes [get]
+func (ws *walletService) GetMyGiftCodes(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	gifts, err := ws.WalletRepository.GetGiftCodes(int(userID))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    gifts,
+	})
+}
+
+// RedeemGiftCode adds the value of a gift code to the wallet.
+// @Summary Redeem a gift code
+// @Description Adds the value of a gift code to the logged-in user's balance. Each code can be redeemed once; dashes and lower case are accepted.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param gift body entity.RedeemGiftCodePayload true "Gift code"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Gift code redeemed successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request or gift code expired"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 404 {object} entity.ResponseError "Gift code not found"
+// @Failure 409 {object} entity.ResponseError "Gift code has already been redeemed"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/wallet/gift-codes/redeem [post]
+func (ws *walletService) RedeemGiftCode(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.RedeemGiftCodePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if strings.TrimSpace(payload.Code) == "" {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "code is required",
+		})
+	}
+
+	gift, err := ws.WalletRepository.RedeemGiftCode(int(userID), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Gift code redeemed successfully",
+		Data: map[string]interface{}{
+			"amount": gift.Amount,
+		},
+	})
+}
+
+// IssueGiftCode creates a gift code without charging a wallet.
+// @Summary Issue a gift code
+// @Description Issues a gift balance code worth between 50.000 and 5.000.000 IDR that is not paid from any wallet, e.g. for corporate accounts funding employees. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param gift body entity.GiftCodePayload true "Gift code"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Gift code issued successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/gift-codes [post]
+func (ws *walletService) IssueGiftCode(c echo.Context) error {
+	userID := c.Get("user").(jwt.MapClaims)["user_id"].(float64)
+
+	var payload entity.GiftCodePayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateGiftCodePayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	gift, err := ws.WalletRepository.IssueGiftCode(int(userID), payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Gift code issued successfully",
+		Data:    gift,
+	})
+}
+
+// GetGiftCodes lists every gift code.
+// @Summary List gift codes
+// @Description Returns all gift codes, bought or issued, newest first. Admin only.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Gift codes retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/gift-codes [get]
+func (ws *walletService) GetGiftCodes(c echo.Context) error {
+	gifts, err := ws.WalletRepository.GetGiftCodes(0)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    gifts,
+	})
+}
+
+func validateWalletTransferPayload(payload entity.WalletTransferPayload) error {
+	if strings.TrimSpace(payload.Recipient) == "" {
+		return fmt.Errorf("400 | recipient email or phone number is required")
+	}
+
+	if payload.Amount < 10000.00 {
+		return fmt.Errorf("400 | transfer amount must be at least 10.000,00 IDR")
+	}
+
+	if payload.Amount > 5000000.00 {
+		return fmt.Errorf("400 | transfer amount cannot exceed 5.000.000,00 IDR")
+	}
+
+	if len(payload.Note) > 255 {
+		return fmt.Errorf("400 | note cannot be longer than 255 characters")
+	}
+
+	return nil
+}
+
+func validateGiftCodePayload(payload entity.GiftCodePayload) error {
+	if payload.Amount < 50000.00 {
+		return fmt.Errorf("400 | gift code amount must be at least 50.000,00 IDR")
+	}
+
+	if payload.Amount > 5000000.00 {
+		return fmt.Errorf("400 | gift code amount cannot exceed 5.000.000,00 IDR")
+	}
+
+	if len(payload.Note) > 255 {
+		return fmt.Errorf("400 | note cannot be longer than 255 characters")
+	}
+
+	return nil
+}
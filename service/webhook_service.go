@@ -0,0 +1,331 @@
+package service
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"lux-hotel/repository"
+	"lux-hotel/utils"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookService interface {
+	GetSubscriptions(c echo.Context) error
+	CreateSubscription(c echo.Context) error
+	UpdateSubscription(c echo.Context) error
+	DeleteSubscription(c echo.Context) error
+	GetDeliveries(c echo.Context) error
+	Redeliver(c echo.Context) error
+}
+
+type webhookService struct {
+	WebhookRepository repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository) WebhookService {
+	return &webhookService{WebhookRepository: webhookRepository}
+}
+
+// GetSubscriptions lists partner webhook subscriptions.
+// @Summary List webhook subscriptions
+// @Description Returns every partner webhook subscription. The signing secret is not included, it is only returned when the subscription is created. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Subscriptions retrieved successfully"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhooks [get]
+func (ws *webhookService) GetSubscriptions(c echo.Context) error {
+	subscriptions, err := ws.WebhookRepository.GetSubscriptions()
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    subscriptions,
+	})
+}
+
+// CreateSubscription registers a partner webhook.
+// @Summary Create a webhook subscription
+// @Description Registers a partner URL for booking.created, booking.settled, booking.cancelled, booking.expired and topup.settled events. Each delivery is signed with the returned secret, which is shown only this once: X-Webhook-Signature is "sha256=" followed by the hex HMAC-SHA256 of "{X-Webhook-Timestamp}.{raw body}". Failed deliveries are retried with exponential backoff. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param subscription body entity.WebhookSubscriptionPayload true "Subscription"
+// @Security ApiKeyAuth
+// @Success 201 {object} entity.ResponseOK "Subscription created successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhooks [post]
+func (ws *webhookService) CreateSubscription(c echo.Context) error {
+	var payload entity.WebhookSubscriptionPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateWebhookSubscriptionPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	subscription, err := ws.WebhookRepository.CreateSubscription(payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(201, entity.ResponseOK{
+		Status:  201,
+		Message: "Subscription created successfully",
+		Data:    subscription,
+	})
+}
+
+// UpdateSubscription changes a partner webhook.
+// @Summary Update a webhook subscription
+// @Description Replaces the name, URL and events of a subscription and optionally pauses it. The secret stays the same. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param subscription body entity.WebhookSubscriptionPayload true "Subscription"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Subscription updated successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid request"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Subscription not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhooks/{id} [put]
+func (ws *webhookService) UpdateSubscription(c echo.Context) error {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	var payload entity.WebhookSubscriptionPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid request",
+		})
+	}
+
+	if err := validateWebhookSubscriptionPayload(payload); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	subscription, err := ws.WebhookRepository.UpdateSubscription(subscriptionID, payload)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Subscription updated successfully",
+		Data:    subscription,
+	})
+}
+
+// DeleteSubscription removes a partner webhook.
+// @Summary Delete a webhook subscription
+// @Description Removes a subscription. Its delivery log is kept and queued deliveries are marked failed. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Subscription deleted successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Subscription not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhooks/{id} [delete]
+func (ws *webhookService) DeleteSubscription(c echo.Context) error {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	if err := ws.WebhookRepository.DeleteSubscription(subscriptionID); err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Subscription deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GetDeliveries shows the delivery log of a partner webhook.
+// @Summary List webhook deliveries
+// @Description Returns the latest 100 deliveries of a subscription with attempts, response status and last error, newest first. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Param status query string false "pending, delivered or failed"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Deliveries retrieved successfully"
+// @Failure 400 {object} entity.ResponseError "Invalid ID"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Subscription not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhooks/{id}/deliveries [get]
+func (ws *webhookService) GetDeliveries(c echo.Context) error {
+	subscriptionID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	deliveries, err := ws.WebhookRepository.GetDeliveries(subscriptionID, c.QueryParam("status"))
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Success",
+		Data:    deliveries,
+	})
+}
+
+// Redeliver queues a webhook delivery again.
+// @Summary Redeliver a webhook event
+// @Description Sends a delivered or failed event again with the same event ID and payload, starting a fresh round of retries. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Security ApiKeyAuth
+// @Success 200 {object} entity.ResponseOK "Delivery queued"
+// @Failure 400 {object} entity.ResponseError "Invalid ID or delivery already queued"
+// @Failure 401 {object} entity.ResponseError "Unauthorized access"
+// @Failure 403 {object} entity.ResponseError "Insufficient permission"
+// @Failure 404 {object} entity.ResponseError "Delivery or subscription not found"
+// @Failure 500 {object} entity.ResponseError "Internal server error"
+// @Router /api/admin/webhook-deliveries/{id}/redeliver [post]
+func (ws *webhookService) Redeliver(c echo.Context) error {
+	deliveryID, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		return c.JSON(400, entity.ResponseError{
+			Status:  400,
+			Message: "Invalid ID",
+		})
+	}
+
+	delivery, err := ws.WebhookRepository.Redeliver(deliveryID)
+
+	if err != nil {
+		errCode, _ := strconv.Atoi(err.Error()[:3])
+		errMessage := err.Error()[6:]
+
+		return c.JSON(errCode, entity.ResponseError{
+			Status:  errCode,
+			Message: errMessage,
+		})
+	}
+
+	return c.JSON(200, entity.ResponseOK{
+		Status:  200,
+		Message: "Delivery queued",
+		Data:    delivery,
+	})
+}
+
+func validateWebhookSubscriptionPayload(payload entity.WebhookSubscriptionPayload) error {
+	if payload.Name == "" {
+		return fmt.Errorf("400 | name is required")
+	}
+
+	target, err := url.Parse(payload.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return fmt.Errorf("400 | url must be an http or https address")
+	}
+
+	if len(payload.Events) == 0 {
+		return fmt.Errorf("400 | at least one event is required")
+	}
+
+	for _, event := range payload.Events {
+		if !slices.Contains(utils.WebhookEvents, event) {
+			return fmt.Errorf("400 | unknown event %s", event)
+		}
+	}
+
+	return nil
+}
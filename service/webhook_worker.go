@@ -0,0 +1,38 @@
+package service
+
+import (
+	"log"
+	"lux-hotel/repository"
+	"time"
+)
+
+const (
+	webhookPollInterval = 10 * time.Second
+	webhookBatchSize    = 20
+)
+
+type WebhookWorker interface {
+	Start()
+}
+
+type webhookWorker struct {
+	WebhookRepository repository.WebhookRepository
+}
+
+func NewWebhookWorker(webhookRepository repository.WebhookRepository) WebhookWorker {
+	return &webhookWorker{WebhookRepository: webhookRepository}
+}
+
+// Start delivers queued webhook events in the background for the lifetime of the process
+func (ww *webhookWorker) Start() {
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := ww.WebhookRepository.DeliverPending(webhookBatchSize); err != nil {
+				log.Println("Webhook delivery failed:", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// Booking codes leave out 0, O, 1 and I so they survive being read out at the desk
+const bookingCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// Random characters in a booking code, followed by one check character
+const bookingCodeLength = 7
+
+// GenerateBookingCode returns a random booking code such as "K7MQP3XW"
+func GenerateBookingCode() (string, error) {
+	code := make([]byte, bookingCodeLength, bookingCodeLength+1)
+	max := big.NewInt(int64(len(bookingCodeAlphabet)))
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = bookingCodeAlphabet[n.Int64()]
+	}
+
+	return string(append(code, bookingCodeCheckChar(string(code)))), nil
+}
+
+// NormalizeBookingCode undoes the usual ways a typed code differs from the
+// issued one: lower case, spaces and dashes
+func NormalizeBookingCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// ValidBookingCode reports whether code is well formed and its check character
+// matches, which catches any single mistyped character and most swapped pairs
+func ValidBookingCode(code string) bool {
+	if len(code) != bookingCodeLength+1 {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(bookingCodeAlphabet, code[i]) < 0 {
+			return false
+		}
+	}
+
+	return bookingCodeCheckChar(code[:bookingCodeLength]) == code[bookingCodeLength]
+}
+
+// LegacyBookingCode reports whether code looks like the date based codes issued
+// before: the booking date followed by the hotel and room IDs, all digits
+func LegacyBookingCode(code string) bool {
+	if len(code) < 10 || len(code) > 30 {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bookingCodeCheckChar computes the Luhn mod N check character of payload
+func bookingCodeCheckChar(payload string) byte {
+	n := len(bookingCodeAlphabet)
+	factor := 2
+	sum := 0
+
+	for i := len(payload) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(bookingCodeAlphabet, payload[i])
+		sum += addend/n + addend%n
+
+		factor = 3 - factor
+	}
+
+	return bookingCodeAlphabet[(n-sum%n)%n]
+}
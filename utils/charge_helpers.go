@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"lux-hotel/entity"
+	"math"
+)
+
+// RoundPrice rounds an amount to whole rupiah. Rupiah are not split in
+// practice and Midtrans only accepts whole amounts.
+func RoundPrice(amount float64) float64 {
+	return math.Round(amount)
+}
+
+// CalculateCharges applies the hotel's tax and fee rules to a room subtotal.
+// Exclusive charges are returned with their total, inclusive charges only report
+// the portion already contained in the subtotal and are not added to the total.
+// Per-night fixed charges are counted once per room per night. Percentages are
+// taken of the net subtotal, what is left once every inclusive charge is out.
+func CalculateCharges(subTotal float64, roomNights int, rules []entity.HotelCharge) ([]entity.BookingCharge, float64) {
+	// Inclusive fixed amounts come off the gross subtotal, then the inclusive
+	// percentages are all carved out of what remains
+	inclusiveRate, inclusiveFixed := 0.0, 0.0
+	for _, rule := range rules {
+		if !rule.Inclusive {
+			continue
+		}
+
+		if rule.ChargeType == "percentage" {
+			inclusiveRate += rule.Amount
+		} else {
+			inclusiveFixed += fixedChargeAmount(rule, roomNights)
+		}
+	}
+	netSubTotal := max(subTotal-inclusiveFixed, 0) / (1 + inclusiveRate/100)
+
+	charges := make([]entity.BookingCharge, 0, len(rules))
+	exclusiveTotal := 0.0
+
+	for _, rule := range rules {
+		var amount float64
+
+		switch rule.ChargeType {
+		case "percentage":
+			base := netSubTotal
+			if !rule.Inclusive && rule.Compound {
+				base = netSubTotal + exclusiveTotal
+			}
+			amount = base * rule.Amount / 100
+		default:
+			amount = fixedChargeAmount(rule, roomNights)
+		}
+
+		amount = RoundPrice(amount)
+		if !rule.Inclusive {
+			exclusiveTotal += amount
+		}
+
+		charges = append(charges, entity.BookingCharge{
+			HotelChargeID: rule.ID,
+			Name:          rule.Name,
+			ChargeType:    rule.ChargeType,
+			Rate:          rule.Amount,
+			Basis:         rule.Basis,
+			Inclusive:     rule.Inclusive,
+			Compound:      rule.Compound,
+			Amount:        amount,
+		})
+	}
+
+	return charges, RoundPrice(exclusiveTotal)
+}
+
+// fixedChargeAmount is what a fixed charge comes to for the stay
+func fixedChargeAmount(rule entity.HotelCharge, roomNights int) float64 {
+	if rule.Basis == "per_night" {
+		return rule.Amount * float64(roomNights)
+	}
+
+	return rule.Amount
+}
+
+// CalculateDiscount returns the amount a promo code takes off a room subtotal
+func CalculateDiscount(promo entity.PromoCode, subTotal float64) float64 {
+	discount := promo.DiscountValue
+
+	if promo.DiscountType == "percentage" {
+		discount = subTotal * promo.DiscountValue / 100
+
+		if promo.MaxDiscount > 0 && discount > promo.MaxDiscount {
+			discount = promo.MaxDiscount
+		}
+	}
+
+	if discount > subTotal {
+		discount = subTotal
+	}
+
+	return RoundPrice(discount)
+}
+
+// ChargeRules rebuilds the rules a booking was priced with from its charge lines
+func ChargeRules(charges []entity.BookingCharge) []entity.HotelCharge {
+	rules := make([]entity.HotelCharge, 0, len(charges))
+
+	for _, charge := range charges {
+		rules = append(rules, entity.HotelCharge{
+			ID:         charge.HotelChargeID,
+			Name:       charge.Name,
+			ChargeType: charge.ChargeType,
+			Amount:     charge.Rate,
+			Basis:      charge.Basis,
+			Inclusive:  charge.Inclusive,
+			Compound:   charge.Compound,
+		})
+	}
+
+	return rules
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"lux-hotel/entity"
+	"time"
+)
+
+// DepositTerms works out how a stay may be paid under the hotel's deposit
+// policy. A zero percent means the full price is due at booking. With a
+// deposit, a nil due date means the balance is paid at check-in.
+func DepositTerms(hotel entity.Hotel, checkIn time.Time, nights int, today time.Time) (int, *time.Time) {
+	if hotel.DepositPercent <= 0 || hotel.DepositPercent >= 100 || nights < hotel.DepositMinNights {
+		return 0, nil
+	}
+
+	if hotel.BalanceAtDesk {
+		return hotel.DepositPercent, nil
+	}
+
+	// Stays booked after the balance would be due are paid in full
+	dueDate := checkIn.AddDate(0, 0, -hotel.BalanceDueDays)
+	if !dueDate.After(today) {
+		return 0, nil
+	}
+
+	return hotel.DepositPercent, &dueDate
+}
+
+// DepositAmount is the part of a booking's total paid up front
+func DepositAmount(totalPrice float64, percent int) float64 {
+	return RoundPrice(totalPrice * float64(percent) / 100)
+}
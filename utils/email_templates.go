@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+)
+
+// Email templates, one per event guests are told about
+const (
+	EmailBookingCreated   = "booking_created"
+	EmailBookingPaid      = "booking_paid"
+	EmailBookingExpired   = "booking_expired"
+	EmailBookingCancelled = "booking_cancelled"
+	EmailDepositPaid      = "deposit_paid"
+	EmailBalanceReminder  = "balance_reminder"
+	EmailBalanceOverdue   = "balance_overdue"
+	EmailTopUpSettled     = "topup_settled"
+	EmailTransferReceived = "transfer_received"
+)
+
+// EmailData fills in the placeholders of an email template
+type EmailData struct {
+	Name        string
+	OrderID     string
+	BookingCode string
+	HotelName   string
+	CheckIn     string
+	CheckOut    string
+	Amount      float64
+	AmountPaid  float64
+	BalanceDue  float64 // only set while a deposit booking has an open balance
+	DueDate     string  // empty when the balance is paid at check-in
+	Sender      string  // who sent a wallet transfer
+}
+
+type EmailContent struct {
+	Locale   string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+type emailCopy struct {
+	Subject string
+	Intro   string
+}
+
+var emailCopies = map[string]map[string]emailCopy{
+	EmailBookingCreated: {
+		"id": {"Pemesanan {{.BookingCode}} di {{.HotelName}} telah dibuat", "Pemesanan Anda telah kami terima. Silakan selesaikan pembayaran agar kamar tetap tersedia untuk Anda."},
+		"en": {"Your booking {{.BookingCode}} at {{.HotelName}} is created", "We have received your booking. Please complete the payment to keep the rooms reserved for you."},
+	},
+	EmailBookingPaid: {
+		"id": {"Pembayaran pemesanan {{.BookingCode}} berhasil", "Pembayaran Anda telah kami terima dan pemesanan Anda sudah terkonfirmasi. Sampai jumpa di {{.HotelName}}!"},
+		"en": {"Payment for booking {{.BookingCode}} received", "We have received your payment and your booking is confirmed. See you at {{.HotelName}}!"},
+	},
+	EmailBookingExpired: {
+		"id": {"Pemesanan {{.BookingCode}} kedaluwarsa", "Batas waktu pembayaran telah lewat sehingga pemesanan Anda dibatalkan secara otomatis."},
+		"en": {"Booking {{.BookingCode}} has expired", "The payment window has passed, so your booking was cancelled automatically."},
+	},
+	EmailBookingCancelled: {
+		"id": {"Pemesanan {{.BookingCode}} dibatalkan", "Pemesanan Anda telah dibatalkan."},
+		"en": {"Booking {{.BookingCode}} is cancelled", "Your booking has been cancelled."},
+	},
+	EmailDepositPaid: {
+		"id": {"Deposit pemesanan {{.BookingCode}} berhasil", "Deposit Anda telah kami terima dan pemesanan Anda sudah terkonfirmasi. Mohon lunasi sisa pembayaran sesuai batas waktu di bawah ini."},
+		"en": {"Deposit for booking {{.BookingCode}} received", "We have received your deposit and your booking is confirmed. Please pay the remaining balance by the due date below."},
+	},
+	EmailBalanceReminder: {
+		"id": {"Sisa pembayaran pemesanan {{.BookingCode}} segera jatuh tempo", "Sisa pembayaran pemesanan Anda akan segera jatuh tempo. Mohon lunasi sebelum batas waktu, jika tidak pemesanan akan dibatalkan secara otomatis."},
+		"en": {"Balance for booking {{.BookingCode}} is due soon", "The remaining balance of your booking is due soon. Please pay it before the due date, otherwise the booking is cancelled automatically."},
+	},
+	EmailBalanceOverdue: {
+		"id": {"Pemesanan {{.BookingCode}} dibatalkan", "Sisa pembayaran tidak dilunasi sebelum batas waktu sehingga pemesanan Anda dibatalkan secara otomatis. Deposit tidak dapat dikembalikan."},
+		"en": {"Booking {{.BookingCode}} is cancelled", "The remaining balance was not paid by the due date, so your booking was cancelled automatically. The deposit is non-refundable."},
+	},
+	EmailTopUpSettled: {
+		"id": {"Isi saldo berhasil", "Isi saldo Anda telah berhasil dan saldo sudah dapat digunakan."},
+		"en": {"Top-up successful", "Your top-up was successful and the balance is ready to use."},
+	},
+	EmailTransferReceived: {
+		"id": {"Anda menerima saldo dari {{.Sender}}", "{{.Sender}} telah mengirim saldo ke akun Anda dan saldo sudah dapat digunakan."},
+		"en": {"You received balance from {{.Sender}}", "{{.Sender}} sent balance to your account and it is ready to use."},
+	},
+}
+
+var emailLabels = map[string]map[string]string{
+	"id": {
+		"greeting":     "Halo {{.Name}},",
+		"closing":      "Terima kasih,\nLux Hotel",
+		"hotel":        "Hotel",
+		"booking_code": "Kode pemesanan",
+		"order_id":     "ID pesanan",
+		"stay":         "Menginap",
+		"total":        "Total",
+		"amount":       "Jumlah",
+		"paid":         "Dibayar",
+		"balance":      "Sisa pembayaran",
+		"due_date":     "Jatuh tempo",
+		"at_check_in":  "Saat check-in",
+		"from":         "Dari",
+		"reference":    "Referensi",
+	},
+	"en": {
+		"greeting":     "Hi {{.Name}},",
+		"closing":      "Thank you,\nLux Hotel",
+		"hotel":        "Hotel",
+		"booking_code": "Booking code",
+		"order_id":     "Order ID",
+		"stay":         "Stay",
+		"total":        "Total",
+		"amount":       "Amount",
+		"paid":         "Paid",
+		"balance":      "Balance",
+		"due_date":     "Due by",
+		"at_check_in":  "At check-in",
+		"from":         "From",
+		"reference":    "Reference",
+	},
+}
+
+var emailHTMLLayout = htmltemplate.Must(htmltemplate.New("email").Parse(`<!DOCTYPE html>
+<html lang="{{.Locale}}">
+<body style="font-family: Arial, sans-serif; color: #222;">
+<p>{{.Greeting}}</p>
+<p>{{.Intro}}</p>
+<table cellpadding="4" style="border-collapse: collapse;">
+{{range .Details}}<tr><td style="color: #666;">{{.Label}}</td><td><strong>{{.Value}}</strong></td></tr>
+{{end}}</table>
+<p>{{range .Closing}}{{.}}<br>{{end}}</p>
+</body>
+</html>
+`))
+
+type emailDetail struct {
+	Label string
+	Value string
+}
+
+// RenderEmail builds the subject, plain text and HTML body of an email in the
+// guest's language. Unknown locales fall back to Indonesian.
+func RenderEmail(name, locale string, data EmailData) (*EmailContent, error) {
+	copies, ok := emailCopies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	if _, ok := copies[locale]; !ok {
+		locale = "id"
+	}
+	labels := emailLabels[locale]
+
+	subject, err := fillEmailText(copies[locale].Subject, data)
+	if err != nil {
+		return nil, err
+	}
+
+	intro, err := fillEmailText(copies[locale].Intro, data)
+	if err != nil {
+		return nil, err
+	}
+
+	greeting, err := fillEmailText(labels["greeting"], data)
+	if err != nil {
+		return nil, err
+	}
+
+	var details []emailDetail
+	switch name {
+	case EmailTopUpSettled:
+		details = []emailDetail{
+			{labels["order_id"], data.OrderID},
+			{labels["amount"], formatRupiah(data.Amount)},
+		}
+	case EmailTransferReceived:
+		details = []emailDetail{
+			{labels["from"], data.Sender},
+			{labels["reference"], data.OrderID},
+			{labels["amount"], formatRupiah(data.Amount)},
+		}
+	default:
+		details = []emailDetail{
+			{labels["hotel"], data.HotelName},
+			{labels["booking_code"], data.BookingCode},
+			{labels["order_id"], data.OrderID},
+			{labels["stay"], data.CheckIn + " - " + data.CheckOut},
+			{labels["total"], formatRupiah(data.Amount)},
+		}
+
+		if data.BalanceDue > 0 {
+			dueDate := data.DueDate
+			if dueDate == "" {
+				dueDate = labels["at_check_in"]
+			}
+
+			details = append(details,
+				emailDetail{labels["paid"], formatRupiah(data.AmountPaid)},
+				emailDetail{labels["balance"], formatRupiah(data.BalanceDue)},
+				emailDetail{labels["due_date"], dueDate},
+			)
+		}
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n\n%s\n\n", greeting, intro)
+	for _, detail := range details {
+		fmt.Fprintf(&text, "%s: %s\n", detail.Label, detail.Value)
+	}
+	fmt.Fprintf(&text, "\n%s\n", labels["closing"])
+
+	var html bytes.Buffer
+	err = emailHTMLLayout.Execute(&html, map[string]interface{}{
+		"Locale":   locale,
+		"Greeting": greeting,
+		"Intro":    intro,
+		"Details":  details,
+		"Closing":  strings.Split(labels["closing"], "\n"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailContent{
+		Locale:   locale,
+		Subject:  subject,
+		TextBody: text.String(),
+		HTMLBody: html.String(),
+	}, nil
+}
+
+func fillEmailText(text string, data EmailData) (string, error) {
+	tmpl, err := template.New("text").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"math"
+	"os"
+)
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations
+const EarthRadiusKm = 6371.0
+
+// UsePostGIS reports whether geo queries should use the PostGIS extension
+// instead of the plain SQL Haversine formula
+func UsePostGIS() bool {
+	return os.Getenv("GEO_USE_POSTGIS") == "true"
+}
+
+// BoundingBox returns the latitude and longitude limits of a square around a
+// point that contains every location within radiusKm of it
+func BoundingBox(latitude, longitude, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	latDelta := radiusKm / (EarthRadiusKm * math.Pi / 180)
+	lngDelta := 180.0
+
+	// Near the poles every longitude is within range
+	if cosLat := math.Cos(latitude * math.Pi / 180); cosLat > 0.0001 {
+		lngDelta = math.Min(latDelta/cosLat, 180)
+	}
+
+	return latitude - latDelta, latitude + latDelta, longitude - lngDelta, longitude + lngDelta
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Gift codes carry money, so they are long enough not to be guessed
+const giftCodeLength = 16
+
+// GenerateGiftCode returns a random gift code such as "K7MQP3XWA2B4C5D6",
+// drawn from the same unambiguous characters as booking codes
+func GenerateGiftCode() (string, error) {
+	code := make([]byte, giftCodeLength)
+	max := big.NewInt(int64(len(bookingCodeAlphabet)))
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = bookingCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// NormalizeGiftCode accepts a gift code typed in groups or in lower case
+func NormalizeGiftCode(code string) string {
+	return NormalizeBookingCode(code)
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Largest calendar file read from a feed
+const maxICSSize = 2 << 20
+
+type ICSEvent struct {
+	UID     string
+	Summary string
+	Start   time.Time // date the stay starts
+	End     time.Time // exclusive, the check-out date
+}
+
+// BuildICS writes all-day events as an iCalendar file. stamp is used as
+// DTSTAMP so unchanged calendars export identically.
+func BuildICS(name string, events []ICSEvent, stamp time.Time) string {
+	var sb strings.Builder
+
+	writeICSLine(&sb, "BEGIN:VCALENDAR")
+	writeICSLine(&sb, "VERSION:2.0")
+	writeICSLine(&sb, "PRODID:-//Lux Hotel//Availability//EN")
+	writeICSLine(&sb, "CALSCALE:GREGORIAN")
+	writeICSLine(&sb, "METHOD:PUBLISH")
+	writeICSLine(&sb, "X-WR-CALNAME:"+escapeICSText(name))
+
+	for _, event := range events {
+		writeICSLine(&sb, "BEGIN:VEVENT")
+		writeICSLine(&sb, "UID:"+event.UID)
+		writeICSLine(&sb, "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"))
+		writeICSLine(&sb, "DTSTART;VALUE=DATE:"+event.Start.Format("20060102"))
+		writeICSLine(&sb, "DTEND;VALUE=DATE:"+event.End.Format("20060102"))
+		writeICSLine(&sb, "SUMMARY:"+escapeICSText(event.Summary))
+		writeICSLine(&sb, "TRANSP:OPAQUE")
+		writeICSLine(&sb, "END:VEVENT")
+	}
+
+	writeICSLine(&sb, "END:VCALENDAR")
+
+	return sb.String()
+}
+
+// ParseICS reads the events of an iCalendar file. Times are reduced to their
+// date, events without an end block just their start day.
+func ParseICS(r io.Reader) ([]ICSEvent, error) {
+	var events []ICSEvent
+	var current *ICSEvent
+
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		// Drop parameters such as ;VALUE=DATE or ;TZID=Asia/Jakarta
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &ICSEvent{}
+		case name == "END" && value == "VEVENT" && current != nil:
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no start date", current.UID)
+			}
+
+			if !current.End.After(current.Start) {
+				current.End = current.Start.AddDate(0, 0, 1)
+			}
+
+			if current.UID == "" {
+				current.UID = fmt.Sprintf("%s-%s", current.Start.Format("20060102"), current.End.Format("20060102"))
+			}
+
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeICSText(value)
+		case name == "DTSTART":
+			if current.Start, err = parseICSDate(value); err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			if current.End, err = parseICSDate(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// OpenICSSource opens a calendar from an http(s) URL or a local file path
+func OpenICSSource(source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.Open(source)
+	}
+
+	client := http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("calendar responded with %s", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	// A cut off feed would look like its later events were removed
+	data, err := io.ReadAll(io.LimitReader(r, maxICSSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxICSSize {
+		return nil, fmt.Errorf("calendar is larger than %d bytes", maxICSSize)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxICSSize)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// Lines starting with a space or tab continue the previous one
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid calendar date %q", value)
+	}
+
+	return time.Parse("20060102", value[:8])
+}
+
+// writeICSLine ends lines with CRLF and folds them at 75 octets as RFC 5545 asks
+func writeICSLine(sb *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		// Do not split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+
+	sb.WriteString(line + "\r\n")
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+var icsTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
+
+func unescapeICSText(text string) string {
+	return icsTextUnescaper.Replace(text)
+}
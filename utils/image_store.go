@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Longest side of generated thumbnails, in pixels
+const ThumbnailSize = 320
+
+// MaxImageSize is the largest upload accepted by the image store
+const MaxImageSize = 5 << 20
+
+// MaxImagePixels caps the decoded size of an upload. A small compressed file
+// can declare huge dimensions and would take gigabytes to decode.
+const MaxImagePixels = 40_000_000
+
+// UploadURLPrefix is the path uploaded files are served from
+const UploadURLPrefix = "/uploads"
+
+// ErrInvalidImage is returned for uploads that are too large or not a supported image
+var ErrInvalidImage = errors.New("invalid image")
+
+type StoredImage struct {
+	URL          string
+	ThumbnailURL string
+}
+
+// UploadDir is where uploaded files are written, served under UploadURLPrefix
+func UploadDir() string {
+	if dir := os.Getenv("UPLOAD_DIR"); dir != "" {
+		return dir
+	}
+
+	return "uploads"
+}
+
+// StoreImage saves a JPEG or PNG upload under the given folder together with a
+// downscaled thumbnail and returns the public URLs of both.
+func StoreImage(folder string, file io.Reader) (*StoredImage, error) {
+	data, err := io.ReadAll(io.LimitReader(file, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("%w: larger than %d MB", ErrInvalidImage, MaxImageSize>>20)
+	}
+
+	var ext string
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	default:
+		return nil, fmt.Errorf("%w: only JPEG and PNG are supported", ErrInvalidImage)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if config.Width*config.Height > MaxImagePixels {
+		return nil, fmt.Errorf("%w: larger than %d megapixels", ErrInvalidImage, MaxImagePixels/1_000_000)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	dir := filepath.Join(UploadDir(), folder)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	name := uuid.New().String()
+	imagePath := filepath.Join(dir, name+ext)
+	thumbPath := filepath.Join(dir, name+"_thumb"+ext)
+
+	if err := os.WriteFile(imagePath, data, 0o644); err != nil {
+		return nil, err
+	}
+
+	thumb, err := os.Create(thumbPath)
+	if err != nil {
+		os.Remove(imagePath)
+		return nil, err
+	}
+	defer thumb.Close()
+
+	resized := resizeImage(img, ThumbnailSize)
+	if ext == ".png" {
+		err = png.Encode(thumb, resized)
+	} else {
+		err = jpeg.Encode(thumb, resized, &jpeg.Options{Quality: 80})
+	}
+
+	if err != nil {
+		os.Remove(imagePath)
+		os.Remove(thumbPath)
+		return nil, err
+	}
+
+	return &StoredImage{
+		URL:          imageURL(folder, name+ext),
+		ThumbnailURL: imageURL(folder, name+"_thumb"+ext),
+	}, nil
+}
+
+// RemoveImage deletes a stored file by its public URL, ignoring files that are already gone
+func RemoveImage(url string) {
+	if !strings.HasPrefix(url, UploadURLPrefix+"/") {
+		return
+	}
+
+	relative := filepath.FromSlash(strings.TrimPrefix(url, UploadURLPrefix+"/"))
+	os.Remove(filepath.Join(UploadDir(), filepath.Clean(relative)))
+}
+
+func imageURL(folder, name string) string {
+	return UploadURLPrefix + "/" + filepath.ToSlash(filepath.Join(folder, name))
+}
+
+// resizeImage scales an image down so its longest side is at most maxSide,
+// averaging the source pixels covered by each target pixel
+func resizeImage(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSide && height <= maxSide {
+		return src
+	}
+
+	newWidth, newHeight := maxSide, height*maxSide/width
+	if height > width {
+		newWidth, newHeight = width*maxSide/height, maxSide
+	}
+	newWidth, newHeight = max(newWidth, 1), max(newHeight, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+
+	for y := 0; y < newHeight; y++ {
+		y0 := bounds.Min.Y + y*height/newHeight
+		y1 := max(bounds.Min.Y+(y+1)*height/newHeight, y0+1)
+
+		for x := 0; x < newWidth; x++ {
+			x0 := bounds.Min.X + x*width/newWidth
+			x1 := max(bounds.Min.X+(x+1)*width/newWidth, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
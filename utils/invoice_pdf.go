@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"fmt"
+	"lux-hotel/entity"
+	"strings"
+)
+
+const (
+	invoiceTitleSize = 16.0
+	invoiceTextSize  = 9.0
+)
+
+// RenderInvoicePDF prints an invoice and booking confirmation. Lines and totals
+// come from the invoice itself, so later changes to the booking do not alter them.
+func RenderInvoicePDF(document entity.InvoiceDocument) []byte {
+	doc := NewPDFDocument()
+	width := PDFLineWidth(invoiceTextSize)
+	booking := document.Booking
+
+	line := func(label, value string) {
+		doc.Text(invoiceColumns(label, value, width), invoiceTextSize, false)
+	}
+
+	doc.Text(document.Hotel.Name, invoiceTitleSize, true)
+	doc.Text(document.Hotel.Location, invoiceTextSize, false)
+	if document.Hotel.ContactNumber != "" || document.Hotel.Email != "" {
+		doc.Text(strings.TrimSpace(document.Hotel.ContactNumber+"  "+document.Hotel.Email), invoiceTextSize, false)
+	}
+	doc.Blank(invoiceTextSize)
+
+	doc.Text("INVOICE & BOOKING CONFIRMATION", invoiceTextSize+2, true)
+	doc.Rule()
+	line("Invoice number", document.Invoice.InvoiceNumber)
+	line("Issued", document.Invoice.IssuedAt.Format("02 Jan 2006"))
+	line("Order ID", booking.OrderID)
+	line("Booking code", booking.BookingCode)
+	doc.Blank(invoiceTextSize)
+
+	line("Guest", strings.TrimSpace(document.Guest.FirstName+" "+document.Guest.LastName))
+	line("Email", document.Guest.Email)
+	line("Check-in", fmt.Sprintf("%s from %s", booking.CheckIn[:10], document.Hotel.CheckInTime))
+	line("Check-out", fmt.Sprintf("%s until %s", booking.CheckOut[:10], document.Hotel.CheckOutTime))
+	line("Nights", fmt.Sprintf("%d", booking.TotalDays))
+	doc.Blank(invoiceTextSize)
+
+	invoice := document.Invoice
+
+	doc.Text("Rooms", invoiceTextSize, true)
+	doc.Rule()
+	for _, room := range invoice.Rooms {
+		// Room type bookings may not have a room yet when invoiced
+		name := "Room " + room.RoomNumber
+		if room.RoomNumber == "" {
+			name = room.RoomTypeName
+		}
+		if name == "" {
+			name = "Room"
+		}
+
+		label := fmt.Sprintf("%s, %d night(s) x %s", name, booking.TotalDays, formatRupiah(room.RoomRate))
+		line(label, formatRupiah(room.SubTotal))
+	}
+	doc.Rule()
+
+	line("Subtotal", formatRupiah(invoice.SubTotal))
+
+	if invoice.Discount > 0 {
+		line("Discount ("+invoice.PromoCode+")", "-"+formatRupiah(invoice.Discount))
+	}
+
+	for _, charge := range invoice.Charges {
+		label := charge.Name
+		if charge.ChargeType == "percentage" {
+			label += fmt.Sprintf(" %g%%", charge.Rate)
+		}
+		if charge.Inclusive {
+			label += " (included)"
+		}
+		line(label, formatRupiah(charge.Amount))
+	}
+
+	doc.Rule()
+	doc.Text(invoiceColumns("TOTAL", formatRupiah(invoice.TotalPrice), width), invoiceTextSize, true)
+	if booking.TotalPrice != invoice.TotalPrice {
+		line("Total after later changes to the booking", formatRupiah(booking.TotalPrice))
+	}
+	doc.Blank(invoiceTextSize)
+
+	if len(document.Payments) > 0 {
+		doc.Text("Payments", invoiceTextSize, true)
+		doc.Rule()
+		for _, payment := range document.Payments {
+			label := payment.PaymentMethod
+			if payment.Installment != "" && payment.Installment != "full" {
+				label += " (" + payment.Installment + ")"
+			}
+			if payment.VANumber != "" {
+				label += " " + payment.VANumber
+			}
+			if payment.PaymentDate != nil {
+				label = payment.PaymentDate.Format("02 Jan 2006") + "  " + label
+			}
+			line(label, formatRupiah(payment.TotalAmount))
+		}
+		doc.Rule()
+	}
+
+	// Payment fees are not part of the total, so paid and due come from the booking
+	line("Amount paid", formatRupiah(booking.AmountPaid))
+	if balanceDue := RoundPrice(booking.TotalPrice - booking.AmountPaid); balanceDue > 0 {
+		due := "at check-in"
+		if booking.BalanceDueDate != nil {
+			due = "by " + booking.BalanceDueDate.Format("02 Jan 2006")
+		}
+		doc.Text(invoiceColumns("BALANCE DUE "+due, formatRupiah(balanceDue), width), invoiceTextSize, true)
+	}
+
+	return doc.Bytes()
+}
+
+// invoiceColumns puts the label on the left and right-aligns the value
+func invoiceColumns(label, value string, width int) string {
+	gap := width - len([]rune(label)) - len([]rune(value))
+	if gap < 1 {
+		gap = 1
+	}
+
+	return label + strings.Repeat(" ", gap) + value
+}
+
+// formatRupiah prints an amount in rupiah with thousands separators
+func formatRupiah(amount float64) string {
+	text := FormatAmount(amount)
+	if strings.HasPrefix(text, "-") {
+		return "-Rp " + text[1:]
+	}
+
+	return "Rp " + text
+}
+
+// FormatAmount prints a whole rupiah amount the Indonesian way, e.g. 10.000.000,00
+func FormatAmount(amount float64) string {
+	text := fmt.Sprintf("%.2f", RoundPrice(amount))
+	whole, cents, _ := strings.Cut(text, ".")
+
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+
+	var grouped []string
+	for len(whole) > 3 {
+		grouped = append([]string{whole[len(whole)-3:]}, grouped...)
+		whole = whole[:len(whole)-3]
+	}
+	grouped = append([]string{whole}, grouped...)
+
+	return sign + strings.Join(grouped, ".") + "," + cents
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"lux-hotel/entity"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Mailer interface {
+	Send(email entity.Email) error
+}
+
+// NewMailer sends through SMTP when SMTP_HOST is set. Otherwise emails are
+// written to MAIL_DIR (default "mail") so they can be read during development.
+func NewMailer() Mailer {
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		return &smtpMailer{
+			host:     host,
+			port:     os.Getenv("SMTP_PORT"),
+			username: os.Getenv("SMTP_USERNAME"),
+			password: os.Getenv("SMTP_PASSWORD"),
+			from:     os.Getenv("MAIL_FROM"),
+		}
+	}
+
+	dir := os.Getenv("MAIL_DIR")
+	if dir == "" {
+		dir = "mail"
+	}
+
+	return &fileMailer{dir: dir}
+}
+
+type smtpMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func (m *smtpMailer) Send(email entity.Email) error {
+	port := m.port
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	message, err := buildMIMEMessage(m.from, email)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.host+":"+port, auth, m.from, []string{email.To}, message)
+}
+
+type fileMailer struct {
+	dir string
+}
+
+func (m *fileMailer) Send(email entity.Email) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+
+	message, err := buildMIMEMessage("noreply@lux-hotel.local", email)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), strings.NewReplacer("@", "_at_", "/", "_").Replace(email.To))
+
+	return os.WriteFile(filepath.Join(m.dir, name), message, 0644)
+}
+
+// buildMIMEMessage puts the text and HTML bodies in one multipart/alternative message
+func buildMIMEMessage(from string, email entity.Email) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", email.TextBody},
+		{"text/html; charset=UTF-8", email.HTMLBody},
+	}
+
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "8bit")
+
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
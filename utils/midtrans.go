@@ -6,11 +6,19 @@ import (
 	"fmt"
 	"log"
 	"lux-hotel/entity"
+	"math"
 	"os"
+	"strconv"
 
 	"github.com/go-resty/resty/v2"
 )
 
+// MidtransAmount formats an amount for gross_amount and item prices, which
+// Midtrans takes in whole rupiah
+func MidtransAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount), 'f', 0, 64)
+}
+
 func MidtransPaymentHandler(payload entity.MidtransPaymentPayload) (*entity.MidtransResponse, error) {
 	var response entity.MidtransResponse
 
@@ -69,3 +77,33 @@ func MidtransTransactionStatusHandler(orderID string) (*entity.MidtransResponse,
 
 	return &response, nil
 }
+
+// MidtransCancelHandler cancels a pending transaction so it can no longer be paid
+func MidtransCancelHandler(orderID string) (*entity.MidtransResponse, error) {
+	var response entity.MidtransResponse
+
+	client := resty.New()
+
+	midtransServerKey := os.Getenv("MIDTRANS_SERVER_KEY")
+	encodedKey := base64.StdEncoding.EncodeToString([]byte(midtransServerKey))
+
+	url := os.Getenv("MIDTRANS_BASE_URL") + "/" + orderID + "/cancel"
+
+	resp, err := client.R().
+		SetHeader("Accept", "application/json").
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", fmt.Sprintf("Basic %s", encodedKey)).
+		Post(url)
+
+	if err != nil {
+		log.Println(err)
+		return nil, fmt.Errorf("500 | %v", err)
+	}
+
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		log.Println("Error unmarshalling response:", err)
+		return nil, err
+	}
+
+	return &response, nil
+}
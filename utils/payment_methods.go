@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"lux-hotel/entity"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+var defaultPaymentMethods = []entity.PaymentMethod{
+	{Code: "wallet", Name: "Lux Wallet", Type: "wallet"},
+	{Code: "bca", Name: "BCA Virtual Account", Type: "bank_transfer", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "bni", Name: "BNI Virtual Account", Type: "bank_transfer", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "bri", Name: "BRI Virtual Account", Type: "bank_transfer", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "cimb", Name: "CIMB Niaga Virtual Account", Type: "bank_transfer", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "permata", Name: "Permata Virtual Account", Type: "bank_transfer", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "mandiri", Name: "Mandiri Bill Payment", Type: "echannel", FeeFlat: 4000, MinAmount: 10000},
+	{Code: "qris", Name: "QRIS", Type: "qris", FeePercent: 0.7, MinAmount: 1000, MaxAmount: 10000000},
+	{Code: "gopay", Name: "GoPay", Type: "gopay", FeePercent: 2, MinAmount: 1000, MaxAmount: 20000000},
+	{Code: "credit_card", Name: "Credit Card", Type: "credit_card", FeeFlat: 2000, FeePercent: 2.9, MinAmount: 10000},
+}
+
+// paymentMethodTypes are the types a payment is built for, anything else
+// would be sent to Midtrans as a bank transfer
+var paymentMethodTypes = map[string]bool{
+	"wallet":        true,
+	"bank_transfer": true,
+	"echannel":      true,
+	"qris":          true,
+	"gopay":         true,
+	"credit_card":   true,
+}
+
+// paymentCatalog holds the enabled payment methods once LoadPaymentMethods ran
+var paymentCatalog []entity.PaymentMethod
+
+// PaymentMethods returns the enabled payment methods loaded at startup
+func PaymentMethods() ([]entity.PaymentMethod, error) {
+	if paymentCatalog == nil {
+		return nil, fmt.Errorf("payment methods are not loaded")
+	}
+
+	return slices.Clone(paymentCatalog), nil
+}
+
+// LoadPaymentMethods reads the enabled payment methods once at startup, so a
+// broken catalog stops the server instead of failing payments.
+// PAYMENT_METHODS_FILE replaces the built-in catalog with a JSON file and
+// PAYMENT_METHODS limits it to a comma separated list of codes. Logos without
+// a URL are looked up under PAYMENT_LOGO_BASE_URL.
+func LoadPaymentMethods() error {
+	methods := defaultPaymentMethods
+
+	if path := os.Getenv("PAYMENT_METHODS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		methods = nil
+		if err := json.Unmarshal(data, &methods); err != nil {
+			return fmt.Errorf("invalid payment method catalog: %v", err)
+		}
+
+		for _, method := range methods {
+			if !paymentMethodTypes[method.Type] {
+				return fmt.Errorf("invalid payment method catalog: %s has unknown type %q", method.Code, method.Type)
+			}
+		}
+	}
+
+	enabled := map[string]bool{}
+	for _, code := range strings.Split(os.Getenv("PAYMENT_METHODS"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			enabled[code] = true
+		}
+	}
+
+	logoBaseURL := os.Getenv("PAYMENT_LOGO_BASE_URL")
+	if logoBaseURL == "" {
+		logoBaseURL = "/assets/payment-methods"
+	}
+
+	catalog := []entity.PaymentMethod{}
+
+	for _, method := range methods {
+		if len(enabled) > 0 && !enabled[method.Code] {
+			continue
+		}
+
+		if method.LogoURL == "" {
+			method.LogoURL = strings.TrimRight(logoBaseURL, "/") + "/" + method.Code + ".png"
+		}
+
+		catalog = append(catalog, method)
+	}
+
+	paymentCatalog = catalog
+
+	return nil
+}
+
+// FindPaymentMethod returns the enabled payment method with the given code, or nil
+func FindPaymentMethod(code string) (*entity.PaymentMethod, error) {
+	methods, err := PaymentMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range methods {
+		if methods[i].Code == code {
+			return &methods[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PaymentFee is what the method adds to amount, rounded up to whole rupiah
+func PaymentFee(method entity.PaymentMethod, amount float64) float64 {
+	return math.Ceil(method.FeeFlat + amount*method.FeePercent/100)
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait in PDF points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// PDFDocument lays out lines of monospaced text over as many A4 pages as
+// needed. Output only depends on the text written, so the same content always
+// produces the same bytes.
+type PDFDocument struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func NewPDFDocument() *PDFDocument {
+	doc := &PDFDocument{}
+	doc.addPage()
+
+	return doc
+}
+
+// PDFLineWidth is how many characters fit on a line at the given font size
+func PDFLineWidth(size float64) int {
+	// Courier glyphs are 600/1000 em wide
+	return int((pdfPageWidth - 2*pdfMargin) / (size * 0.6))
+}
+
+// Text writes one line, moving to a new page when the current one is full
+func (d *PDFDocument) Text(text string, size float64, bold bool) {
+	leading := size * 1.4
+	if d.y-leading < pdfMargin {
+		d.addPage()
+	}
+	d.y -= leading
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pdfMargin, d.y, pdfEscape(text))
+}
+
+// Blank leaves an empty line
+func (d *PDFDocument) Blank(size float64) {
+	d.y -= size * 1.4
+}
+
+// Rule draws a horizontal line across the page
+func (d *PDFDocument) Rule() {
+	d.y -= 6
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "%.1f %.1f m %.1f %.1f l S\n", pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+}
+
+func (d *PDFDocument) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// Bytes assembles the finished PDF file
+func (d *PDFDocument) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are fixed, each page then takes a page and a content object
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfEscape makes text safe inside a PDF string literal. The standard fonts
+// only cover Latin-1, anything else is replaced.
+func pdfEscape(text string) string {
+	var sb strings.Builder
+
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchSimilarityThreshold is the minimum trigram word similarity for a typo match
+const SearchSimilarityThreshold = 0.3
+
+// PrefixTSQuery turns free text into a tsquery that requires every word,
+// each matched as a prefix. Anything but letters and digits is dropped so
+// user input cannot inject tsquery operators.
+func PrefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
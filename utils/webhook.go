@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Events partners can subscribe to
+const (
+	WebhookBookingCreated   = "booking.created"
+	WebhookBookingSettled   = "booking.settled"
+	WebhookBookingCancelled = "booking.cancelled"
+	WebhookBookingExpired   = "booking.expired"
+	WebhookTopUpSettled     = "topup.settled"
+)
+
+var WebhookEvents = []string{WebhookBookingCreated, WebhookBookingSettled, WebhookBookingCancelled, WebhookBookingExpired, WebhookTopUpSettled}
+
+const webhookTimeout = 10 * time.Second
+
+// GenerateWebhookSecret returns a random key for signing a subscription's deliveries
+func GenerateWebhookSecret() string {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	return "whsec_" + hex.EncodeToString(key)
+}
+
+// SignWebhook signs "timestamp.body" with HMAC-SHA256. Partners recompute it
+// from the X-Webhook-Timestamp header and the raw body to verify a delivery.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhook posts a signed event and returns the response status. Anything
+// outside 2xx is reported as an error so the delivery is retried.
+func SendWebhook(url, secret, event, eventID string, body []byte) (int, error) {
+	timestamp := time.Now().Unix()
+
+	resp, err := resty.New().SetTimeout(webhookTimeout).R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("X-Webhook-Event", event).
+		SetHeader("X-Webhook-ID", eventID).
+		SetHeader("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10)).
+		SetHeader("X-Webhook-Signature", "sha256="+SignWebhook(secret, timestamp, body)).
+		SetBody(body).
+		Post(url)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode() < 200 || resp.StatusCode() > 299 {
+		return resp.StatusCode(), fmt.Errorf("endpoint responded with %s", resp.Status())
+	}
+
+	return resp.StatusCode(), nil
+}